@@ -3,16 +3,21 @@
 package client
 
 import (
+	comments "github.com/wepala/langfuse-go/api/comments"
 	core "github.com/wepala/langfuse-go/api/core"
 	datasetitems "github.com/wepala/langfuse-go/api/datasetitems"
 	datasetrunitems "github.com/wepala/langfuse-go/api/datasetrunitems"
 	datasets "github.com/wepala/langfuse-go/api/datasets"
 	health "github.com/wepala/langfuse-go/api/health"
 	ingestion "github.com/wepala/langfuse-go/api/ingestion"
+	media "github.com/wepala/langfuse-go/api/media"
+	metrics "github.com/wepala/langfuse-go/api/metrics"
+	models "github.com/wepala/langfuse-go/api/models"
 	observations "github.com/wepala/langfuse-go/api/observations"
 	projects "github.com/wepala/langfuse-go/api/projects"
 	prompts "github.com/wepala/langfuse-go/api/prompts"
 	score "github.com/wepala/langfuse-go/api/score"
+	scoreconfigs "github.com/wepala/langfuse-go/api/scoreconfigs"
 	sessions "github.com/wepala/langfuse-go/api/sessions"
 	trace "github.com/wepala/langfuse-go/api/trace"
 	http "net/http"
@@ -23,15 +28,20 @@ type Client struct {
 	httpClient core.HTTPClient
 	header     http.Header
 
+	Comments        *comments.Client
 	Datasetitems    *datasetitems.Client
 	Datasetrunitems *datasetrunitems.Client
 	Datasets        *datasets.Client
 	Health          *health.Client
 	Ingestion       *ingestion.Client
+	Media           *media.Client
+	Metrics         *metrics.Client
+	Models          *models.Client
 	Observations    *observations.Client
 	Projects        *projects.Client
 	Prompts         *prompts.Client
 	Score           *score.Client
+	Scoreconfigs    *scoreconfigs.Client
 	Sessions        *sessions.Client
 	Trace           *trace.Client
 }
@@ -45,15 +55,20 @@ func NewClient(opts ...core.ClientOption) *Client {
 		baseURL:         options.BaseURL,
 		httpClient:      options.HTTPClient,
 		header:          options.ToHeader(),
+		Comments:        comments.NewClient(opts...),
 		Datasetitems:    datasetitems.NewClient(opts...),
 		Datasetrunitems: datasetrunitems.NewClient(opts...),
 		Datasets:        datasets.NewClient(opts...),
 		Health:          health.NewClient(opts...),
 		Ingestion:       ingestion.NewClient(opts...),
+		Media:           media.NewClient(opts...),
+		Metrics:         metrics.NewClient(opts...),
+		Models:          models.NewClient(opts...),
 		Observations:    observations.NewClient(opts...),
 		Projects:        projects.NewClient(opts...),
 		Prompts:         prompts.NewClient(opts...),
 		Score:           score.NewClient(opts...),
+		Scoreconfigs:    scoreconfigs.NewClient(opts...),
 		Sessions:        sessions.NewClient(opts...),
 		Trace:           trace.NewClient(opts...),
 	}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wepala/langfuse-go/api"
+)
+
+// fixture replays a recorded response body from testdata
+type fixture struct {
+	method string
+	path   string
+	status int
+	file   string
+}
+
+type recordedRequest struct {
+	method string
+	path   string
+	query  map[string][]string
+	body   []byte
+}
+
+// newFixtureServer serves the given fixtures and records every request it receives
+func newFixtureServer(t *testing.T, fixtures ...fixture) (*Client, *[]recordedRequest) {
+	t.Helper()
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, recordedRequest{method: r.Method, path: r.URL.Path, query: r.URL.Query(), body: body})
+		for _, f := range fixtures {
+			if f.method != r.Method || f.path != r.URL.Path {
+				continue
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(f.status)
+			if f.file != "" {
+				data, err := os.ReadFile(filepath.Join("testdata", f.file))
+				require.NoError(t, err)
+				_, _ = w.Write(data)
+			}
+			return
+		}
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(WithBaseURL(server.URL)), &requests
+}
+
+func TestClient_Metrics(t *testing.T) {
+	c, requests := newFixtureServer(t, fixture{http.MethodGet, "/api/public/metrics/daily", http.StatusOK, "metrics_daily.json"})
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	tag := "production"
+	limit := 50
+	metrics, err := c.Metrics.Daily(context.Background(), &api.MetricsDailyRequest{
+		Limit:         &limit,
+		Tags:          []*string{&tag},
+		FromTimestamp: &from,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, *requests, 1)
+	query := (*requests)[0].query
+	assert.Equal(t, []string{"50"}, query["limit"])
+	assert.Equal(t, []string{"production"}, query["tags"])
+	assert.Equal(t, []string{"2024-05-01T00:00:00Z"}, query["fromTimestamp"])
+	assert.NotContains(t, query, "page")
+
+	require.Len(t, metrics.Data, 1)
+	day := metrics.Data[0]
+	assert.Equal(t, "2024-05-01", day.Date)
+	assert.Equal(t, 12, day.CountTraces)
+	assert.Equal(t, 0.42, day.TotalCost)
+	require.Len(t, day.Usage, 2)
+	assert.Equal(t, "gpt-4o", *day.Usage[0].Model)
+	assert.Equal(t, 1500, day.Usage[0].TotalUsage)
+	assert.Nil(t, day.Usage[1].Model)
+	assert.Equal(t, 1, metrics.Meta.TotalPages)
+}
+
+func TestClient_Models(t *testing.T) {
+	c, requests := newFixtureServer(t,
+		fixture{http.MethodGet, "/api/public/models", http.StatusOK, "models_list.json"},
+		fixture{http.MethodDelete, "/api/public/models/model-1", http.StatusNoContent, ""},
+		fixture{http.MethodDelete, "/api/public/models/missing", http.StatusNotFound, "not_found.json"},
+	)
+
+	t.Run("should list models with pricing", func(t *testing.T) {
+		page := 2
+		models, err := c.Models.List(context.Background(), &api.ModelsListRequest{Page: &page})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2"}, (*requests)[len(*requests)-1].query["page"])
+		require.Len(t, models.Data, 1)
+		model := models.Data[0]
+		assert.Equal(t, "gpt-4o", model.ModelName)
+		assert.Equal(t, api.ModelUsageUnitTokens, model.Unit)
+		assert.Equal(t, 0.000015, *model.OutputPrice)
+		assert.Nil(t, model.TotalPrice)
+		assert.True(t, model.IsLangfuseManaged)
+		assert.Equal(t, 3, models.Meta.TotalItems)
+	})
+
+	t.Run("should delete a model without a response body", func(t *testing.T) {
+		assert.NoError(t, c.Models.Delete(context.Background(), "model-1"))
+	})
+
+	t.Run("should decode not found errors", func(t *testing.T) {
+		err := c.Models.Delete(context.Background(), "missing")
+		var notFound *api.NotFoundError
+		require.True(t, errors.As(err, &notFound), "expected NotFoundError got %T", err)
+		assert.Equal(t, http.StatusNotFound, notFound.StatusCode)
+	})
+}
+
+func TestClient_Trace(t *testing.T) {
+	c, requests := newFixtureServer(t,
+		fixture{http.MethodDelete, "/api/public/traces/trace-1", http.StatusOK, "traces_delete.json"},
+		fixture{http.MethodDelete, "/api/public/traces", http.StatusOK, "traces_delete.json"},
+	)
+
+	t.Run("should delete a trace", func(t *testing.T) {
+		response, err := c.Trace.Delete(context.Background(), "trace-1")
+		require.NoError(t, err)
+		assert.Equal(t, "Traces deleted successfully", response.Message)
+	})
+
+	t.Run("should delete multiple traces", func(t *testing.T) {
+		_, err := c.Trace.Deletemultiple(context.Background(), &api.TraceDeleteMultipleRequest{TraceIds: []string{"a", "b"}})
+		require.NoError(t, err)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal((*requests)[len(*requests)-1].body, &body))
+		assert.Equal(t, []interface{}{"a", "b"}, body["traceIds"])
+	})
+}
+
+func TestClient_Comments(t *testing.T) {
+	c, requests := newFixtureServer(t, fixture{http.MethodPost, "/api/public/comments", http.StatusOK, "comments_create.json"})
+	response, err := c.Comments.Create(context.Background(), &api.CreateCommentRequest{
+		ProjectId:  "project-id",
+		ObjectType: "TRACE",
+		ObjectId:   "trace-1",
+		Content:    "looks good",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "comment-1", response.Id)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal((*requests)[0].body, &body))
+	assert.Equal(t, "TRACE", body["objectType"])
+	assert.Equal(t, "looks good", body["content"])
+	assert.NotContains(t, body, "authorUserId")
+}
+
+func TestClient_Datasets(t *testing.T) {
+	c, _ := newFixtureServer(t, fixture{http.MethodGet, "/api/public/datasets", http.StatusOK, "datasets_list.json"})
+	datasets, err := c.Datasets.List(context.Background(), &api.DatasetsListRequest{})
+	require.NoError(t, err)
+	require.Len(t, datasets.Data, 1)
+	assert.Equal(t, "evaluation", datasets.Data[0].Name)
+	assert.Equal(t, 1, datasets.Meta.Page)
+}
@@ -0,0 +1,29 @@
+// This file was auto-generated by Fern from our API Definition.
+
+package api
+
+type CreateCommentRequest struct {
+	// The id of the project to attach the comment to.
+	ProjectId string `json:"projectId"`
+	// The type of the object to attach the comment to (trace, observation, session, prompt).
+	ObjectType string `json:"objectType"`
+	// The id of the object to attach the comment to. If this does not reference a valid existing object, an error will be thrown.
+	ObjectId string `json:"objectId"`
+	// The content of the comment. May include markdown. Currently limited to 3000 characters.
+	Content string `json:"content"`
+	// The id of the user who created the comment.
+	AuthorUserId *string `json:"authorUserId,omitempty"`
+}
+
+type CommentsGetRequest struct {
+	// Page number, starts at 1.
+	Page *int `json:"-"`
+	// Limit of items per page. If you encounter api issues due to too large page sizes, try to reduce the limit
+	Limit *int `json:"-"`
+	// Filter comments by object type (trace, observation, session, prompt).
+	ObjectType *string `json:"-"`
+	// Filter comments by object id. If objectType is not provided, an error will be thrown.
+	ObjectId *string `json:"-"`
+	// Filter comments by author user id.
+	AuthorUserId *string `json:"-"`
+}
@@ -3,8 +3,20 @@
 package api
 
 type CreateDatasetItemRequest struct {
-	DatasetName    string       `json:"datasetName"`
-	Input          interface{}  `json:"input,omitempty"`
-	ExpectedOutput *interface{} `json:"expectedOutput,omitempty"`
-	Id             *string      `json:"id,omitempty"`
+	DatasetName         string       `json:"datasetName"`
+	Input               interface{}  `json:"input,omitempty"`
+	ExpectedOutput      *interface{} `json:"expectedOutput,omitempty"`
+	SourceTraceId       *string      `json:"sourceTraceId,omitempty"`
+	SourceObservationId *string      `json:"sourceObservationId,omitempty"`
+	Id                  *string      `json:"id,omitempty"`
+}
+
+type DatasetItemsListRequest struct {
+	DatasetName         *string `json:"-"`
+	SourceTraceId       *string `json:"-"`
+	SourceObservationId *string `json:"-"`
+	// page number, starts at 1
+	Page *int `json:"-"`
+	// limit of items per page
+	Limit *int `json:"-"`
 }
@@ -5,3 +5,17 @@ package api
 type CreateDatasetRequest struct {
 	Name string `json:"name"`
 }
+
+type DatasetsListRequest struct {
+	// page number, starts at 1
+	Page *int `json:"-"`
+	// limit of items per page
+	Limit *int `json:"-"`
+}
+
+type DatasetsListRunsRequest struct {
+	// page number, starts at 1
+	Page *int `json:"-"`
+	// limit of items per page
+	Limit *int `json:"-"`
+}
@@ -3,5 +3,5 @@
 package api
 
 type IngestionBatchRequest struct {
-	Batch []interface{} `json:"batch,omitempty"`
+	Batch []*IngestionEvent `json:"batch,omitempty"`
 }
@@ -0,0 +1,17 @@
+// This file was auto-generated by Fern from our API Definition.
+
+package api
+
+type GetMediaUploadUrlRequest struct {
+	// The trace ID associated with the media record
+	TraceId string `json:"traceId"`
+	// The observation ID associated with the media record. If the media record is associated directly with a trace, this will be null.
+	ObservationId *string          `json:"observationId,omitempty"`
+	ContentType   MediaContentType `json:"contentType,omitempty"`
+	// The size of the media record in bytes
+	ContentLength int `json:"contentLength"`
+	// The SHA-256 hash of the media record
+	Sha256Hash string `json:"sha256Hash"`
+	// The trace / observation field the media record is associated with. This can be one of `input`, `output`, `metadata`
+	Field string `json:"field"`
+}
@@ -0,0 +1,24 @@
+// This file was auto-generated by Fern from our API Definition.
+
+package api
+
+import (
+	time "time"
+)
+
+type MetricsDailyRequest struct {
+	// page number, starts at 1
+	Page *int `json:"-"`
+	// limit of items per page
+	Limit *int `json:"-"`
+	// Optional filter by the name of the trace
+	TraceName *string `json:"-"`
+	// Optional filter by the userId associated with the trace
+	UserId *string `json:"-"`
+	// Optional filter for metrics where traces include all of these tags
+	Tags []*string `json:"-"`
+	// Optional filter to only include traces on or after a certain datetime (ISO 8601)
+	FromTimestamp *time.Time `json:"-"`
+	// Optional filter to only include traces before a certain datetime (ISO 8601)
+	ToTimestamp *time.Time `json:"-"`
+}
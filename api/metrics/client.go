@@ -0,0 +1,126 @@
+// This file was auto-generated by Fern from our API Definition.
+
+package metrics
+
+import (
+	bytes "bytes"
+	context "context"
+	json "encoding/json"
+	errors "errors"
+	fmt "fmt"
+	api "github.com/wepala/langfuse-go/api"
+	core "github.com/wepala/langfuse-go/api/core"
+	io "io"
+	http "net/http"
+	url "net/url"
+	time "time"
+)
+
+type Client struct {
+	baseURL    string
+	httpClient core.HTTPClient
+	header     http.Header
+}
+
+func NewClient(opts ...core.ClientOption) *Client {
+	options := core.NewClientOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	return &Client{
+		baseURL:    options.BaseURL,
+		httpClient: options.HTTPClient,
+		header:     options.ToHeader(),
+	}
+}
+
+// Get daily metrics of the Langfuse project
+func (c *Client) Daily(ctx context.Context, request *api.MetricsDailyRequest) (*api.DailyMetrics, error) {
+	baseURL := ""
+	if c.baseURL != "" {
+		baseURL = c.baseURL
+	}
+	endpointURL := baseURL + "/" + "api/public/metrics/daily"
+
+	queryParams := make(url.Values)
+	if request.Page != nil {
+		queryParams.Add("page", fmt.Sprintf("%v", *request.Page))
+	}
+	if request.Limit != nil {
+		queryParams.Add("limit", fmt.Sprintf("%v", *request.Limit))
+	}
+	if request.TraceName != nil {
+		queryParams.Add("traceName", fmt.Sprintf("%v", *request.TraceName))
+	}
+	if request.UserId != nil {
+		queryParams.Add("userId", fmt.Sprintf("%v", *request.UserId))
+	}
+	for _, value := range request.Tags {
+		queryParams.Add("tags", fmt.Sprintf("%v", *value))
+	}
+	if request.FromTimestamp != nil {
+		queryParams.Add("fromTimestamp", fmt.Sprintf("%v", request.FromTimestamp.Format(time.RFC3339)))
+	}
+	if request.ToTimestamp != nil {
+		queryParams.Add("toTimestamp", fmt.Sprintf("%v", request.ToTimestamp.Format(time.RFC3339)))
+	}
+	if len(queryParams) > 0 {
+		endpointURL += "?" + queryParams.Encode()
+	}
+
+	errorDecoder := func(statusCode int, body io.Reader) error {
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		apiError := core.NewAPIError(statusCode, errors.New(string(raw)))
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		switch statusCode {
+		case 400:
+			value := new(api.BadRequestError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		case 401:
+			value := new(api.UnauthorizedError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		case 403:
+			value := new(api.ForbiddenError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		case 404:
+			value := new(api.NotFoundError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		}
+		return apiError
+	}
+
+	var response *api.DailyMetrics
+	if err := core.DoRequest(
+		ctx,
+		c.httpClient,
+		endpointURL,
+		http.MethodGet,
+		request,
+		&response,
+		false,
+		c.header,
+		errorDecoder,
+	); err != nil {
+		return response, err
+	}
+	return response, nil
+}
@@ -0,0 +1,34 @@
+// This file was auto-generated by Fern from our API Definition.
+
+package api
+
+import (
+	time "time"
+)
+
+type CreateModelRequest struct {
+	// Name of the model definition. If multiple with the same name exist, they are applied in the following order: (1) custom over built-in, (2) newest according to startTime where model.startTime<observation.startTime
+	ModelName string `json:"modelName"`
+	// Regex pattern which matches this model definition to generation.model. Useful in case of fine-tuned models. If you want to exact match, use `(?i)^modelname$`
+	MatchPattern string `json:"matchPattern"`
+	// Apply only to generations which are newer than this ISO date.
+	StartDate *time.Time     `json:"startDate,omitempty"`
+	Unit      ModelUsageUnit `json:"unit,omitempty"`
+	// Price (USD) per input unit
+	InputPrice *float64 `json:"inputPrice,omitempty"`
+	// Price (USD) per output unit
+	OutputPrice *float64 `json:"outputPrice,omitempty"`
+	// Price (USD) per total units. Cannot be set if input or output price is set.
+	TotalPrice *float64 `json:"totalPrice,omitempty"`
+	// Optional. Tokenizer to be applied to observations which match to this model. See docs for more details.
+	TokenizerId *string `json:"tokenizerId,omitempty"`
+	// Optional. Configuration for the selected tokenizer. Needs to be JSON. See docs for more details.
+	TokenizerConfig interface{} `json:"tokenizerConfig,omitempty"`
+}
+
+type ModelsListRequest struct {
+	// page number, starts at 1
+	Page *int `json:"-"`
+	// limit of items per page
+	Limit *int `json:"-"`
+}
@@ -2,6 +2,10 @@
 
 package api
 
+import (
+	time "time"
+)
+
 type ObservationsGetManyRequest struct {
 	Page                *int    `json:"-"`
 	Limit               *int    `json:"-"`
@@ -10,4 +14,12 @@ type ObservationsGetManyRequest struct {
 	Type                *string `json:"-"`
 	TraceId             *string `json:"-"`
 	ParentObservationId *string `json:"-"`
+	// Only observations with this level will be returned.
+	Level   *ObservationLevel `json:"-"`
+	Model   *string           `json:"-"`
+	Version *string           `json:"-"`
+	// Only observations with a start time on or after this datetime (ISO 8601) will be returned.
+	FromStartTime *time.Time `json:"-"`
+	// Only observations with a start time before this datetime (ISO 8601) will be returned.
+	ToStartTime *time.Time `json:"-"`
 }
@@ -13,6 +13,7 @@ import (
 	io "io"
 	http "net/http"
 	url "net/url"
+	time "time"
 )
 
 type Client struct {
@@ -130,6 +131,21 @@ func (c *Client) Getmany(ctx context.Context, request *api.ObservationsGetManyRe
 	if request.ParentObservationId != nil {
 		queryParams.Add("parentObservationId", fmt.Sprintf("%v", *request.ParentObservationId))
 	}
+	if request.Level != nil {
+		queryParams.Add("level", fmt.Sprintf("%v", *request.Level))
+	}
+	if request.Model != nil {
+		queryParams.Add("model", fmt.Sprintf("%v", *request.Model))
+	}
+	if request.Version != nil {
+		queryParams.Add("version", fmt.Sprintf("%v", *request.Version))
+	}
+	if request.FromStartTime != nil {
+		queryParams.Add("fromStartTime", fmt.Sprintf("%v", request.FromStartTime.Format(time.RFC3339)))
+	}
+	if request.ToStartTime != nil {
+		queryParams.Add("toStartTime", fmt.Sprintf("%v", request.ToStartTime.Format(time.RFC3339)))
+	}
 	if len(queryParams) > 0 {
 		endpointURL += "?" + queryParams.Encode()
 	}
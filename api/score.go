@@ -3,12 +3,15 @@
 package api
 
 type CreateScoreRequest struct {
-	Id            *string `json:"id,omitempty"`
-	TraceId       string  `json:"traceId"`
-	Name          string  `json:"name"`
-	Value         float64 `json:"value"`
-	ObservationId *string `json:"observationId,omitempty"`
-	Comment       *string `json:"comment,omitempty"`
+	Id      *string `json:"id,omitempty"`
+	TraceId string  `json:"traceId"`
+	Name    string  `json:"name"`
+	// The value of the score. Must be passed as string for categorical scores, and numeric for boolean and numeric scores
+	Value         *CreateScoreValue `json:"value"`
+	ObservationId *string           `json:"observationId,omitempty"`
+	Comment       *string           `json:"comment,omitempty"`
+	// When set, must match the score value's type. If not set, will be inferred from the score value
+	DataType *ScoreDataType `json:"dataType,omitempty"`
 }
 
 type ScoreGetRequest struct {
@@ -0,0 +1,23 @@
+// This file was auto-generated by Fern from our API Definition.
+
+package api
+
+type CreateScoreConfigRequest struct {
+	Name     string        `json:"name"`
+	DataType ScoreDataType `json:"dataType,omitempty"`
+	// Configure custom categories for categorical scores. Pass a list of objects with `label` and `value` properties. Categories are autogenerated for boolean configs and cannot be passed
+	Categories []*ConfigCategory `json:"categories,omitempty"`
+	// Configure a minimum value for numerical scores. If not set, the minimum value defaults to -∞
+	MinValue *float64 `json:"minValue,omitempty"`
+	// Configure a maximum value for numerical scores. If not set, the maximum value defaults to +∞
+	MaxValue *float64 `json:"maxValue,omitempty"`
+	// Description is shown across the Langfuse UI and can be used to e.g. explain the config categories in detail, why a numeric range was set, or provide additional context on config name or usage
+	Description *string `json:"description,omitempty"`
+}
+
+type ScoreConfigsGetRequest struct {
+	// page number, starts at 1
+	Page *int `json:"-"`
+	// limit of items per page
+	Limit *int `json:"-"`
+}
@@ -0,0 +1,18 @@
+// This file was auto-generated by Fern from our API Definition.
+
+package api
+
+import (
+	time "time"
+)
+
+type SessionsListRequest struct {
+	// page number, starts at 1
+	Page *int `json:"-"`
+	// limit of items per page
+	Limit *int `json:"-"`
+	// Optional filter to only include sessions created on or after a certain datetime (ISO 8601)
+	FromTimestamp *time.Time `json:"-"`
+	// Optional filter to only include sessions created before a certain datetime (ISO 8601)
+	ToTimestamp *time.Time `json:"-"`
+}
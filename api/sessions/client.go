@@ -12,6 +12,8 @@ import (
 	core "github.com/wepala/langfuse-go/api/core"
 	io "io"
 	http "net/http"
+	url "net/url"
+	time "time"
 )
 
 type Client struct {
@@ -98,3 +100,85 @@ func (c *Client) Get(ctx context.Context, sessionId string) (*api.SessionWithTra
 	}
 	return response, nil
 }
+
+// Get sessions
+func (c *Client) List(ctx context.Context, request *api.SessionsListRequest) (*api.PaginatedSessions, error) {
+	baseURL := ""
+	if c.baseURL != "" {
+		baseURL = c.baseURL
+	}
+	endpointURL := baseURL + "/" + "api/public/sessions"
+
+	queryParams := make(url.Values)
+	if request.Page != nil {
+		queryParams.Add("page", fmt.Sprintf("%v", *request.Page))
+	}
+	if request.Limit != nil {
+		queryParams.Add("limit", fmt.Sprintf("%v", *request.Limit))
+	}
+	if request.FromTimestamp != nil {
+		queryParams.Add("fromTimestamp", fmt.Sprintf("%v", request.FromTimestamp.Format(time.RFC3339)))
+	}
+	if request.ToTimestamp != nil {
+		queryParams.Add("toTimestamp", fmt.Sprintf("%v", request.ToTimestamp.Format(time.RFC3339)))
+	}
+	if len(queryParams) > 0 {
+		endpointURL += "?" + queryParams.Encode()
+	}
+
+	errorDecoder := func(statusCode int, body io.Reader) error {
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		apiError := core.NewAPIError(statusCode, errors.New(string(raw)))
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		switch statusCode {
+		case 400:
+			value := new(api.BadRequestError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		case 401:
+			value := new(api.UnauthorizedError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		case 403:
+			value := new(api.ForbiddenError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		case 404:
+			value := new(api.NotFoundError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		}
+		return apiError
+	}
+
+	var response *api.PaginatedSessions
+	if err := core.DoRequest(
+		ctx,
+		c.httpClient,
+		endpointURL,
+		http.MethodGet,
+		request,
+		&response,
+		false,
+		c.header,
+		errorDecoder,
+	); err != nil {
+		return response, err
+	}
+	return response, nil
+}
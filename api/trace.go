@@ -2,11 +2,29 @@
 
 package api
 
+import (
+	time "time"
+)
+
 type TraceListRequest struct {
 	Page   *int    `json:"-"`
 	Limit  *int    `json:"-"`
 	UserId *string `json:"-"`
 	Name   *string `json:"-"`
 	// Only traces that include all of these tags will be returned.
-	Tags []*string `json:"-"`
+	Tags      []*string `json:"-"`
+	SessionId *string   `json:"-"`
+	// Only traces with a timestamp on or after this datetime (ISO 8601) will be returned.
+	FromTimestamp *time.Time `json:"-"`
+	// Only traces with a timestamp before this datetime (ISO 8601) will be returned.
+	ToTimestamp *time.Time `json:"-"`
+	// Format of the string `[field].[asc/desc]`. Fields are id, timestamp, name, userId, release, version, public, bookmarked, sessionId. Example timestamp.asc
+	OrderBy *string `json:"-"`
+	Release *string `json:"-"`
+	Version *string `json:"-"`
+}
+
+type TraceDeleteMultipleRequest struct {
+	// List of trace IDs to delete
+	TraceIds []string `json:"traceIds,omitempty"`
 }
@@ -13,6 +13,7 @@ import (
 	io "io"
 	http "net/http"
 	url "net/url"
+	time "time"
 )
 
 type Client struct {
@@ -124,6 +125,24 @@ func (c *Client) List(ctx context.Context, request *api.TraceListRequest) (*api.
 	for _, value := range request.Tags {
 		queryParams.Add("tags", fmt.Sprintf("%v", *value))
 	}
+	if request.SessionId != nil {
+		queryParams.Add("sessionId", fmt.Sprintf("%v", *request.SessionId))
+	}
+	if request.FromTimestamp != nil {
+		queryParams.Add("fromTimestamp", fmt.Sprintf("%v", request.FromTimestamp.Format(time.RFC3339)))
+	}
+	if request.ToTimestamp != nil {
+		queryParams.Add("toTimestamp", fmt.Sprintf("%v", request.ToTimestamp.Format(time.RFC3339)))
+	}
+	if request.OrderBy != nil {
+		queryParams.Add("orderBy", fmt.Sprintf("%v", *request.OrderBy))
+	}
+	if request.Release != nil {
+		queryParams.Add("release", fmt.Sprintf("%v", *request.Release))
+	}
+	if request.Version != nil {
+		queryParams.Add("version", fmt.Sprintf("%v", *request.Version))
+	}
 	if len(queryParams) > 0 {
 		endpointURL += "?" + queryParams.Encode()
 	}
@@ -184,3 +203,135 @@ func (c *Client) List(ctx context.Context, request *api.TraceListRequest) (*api.
 	}
 	return response, nil
 }
+
+// Delete a specific trace
+//
+// The unique langfuse identifier of the trace to delete
+func (c *Client) Delete(ctx context.Context, traceId string) (*api.DeleteTraceResponse, error) {
+	baseURL := ""
+	if c.baseURL != "" {
+		baseURL = c.baseURL
+	}
+	endpointURL := fmt.Sprintf(baseURL+"/"+"api/public/traces/%v", traceId)
+
+	errorDecoder := func(statusCode int, body io.Reader) error {
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		apiError := core.NewAPIError(statusCode, errors.New(string(raw)))
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		switch statusCode {
+		case 400:
+			value := new(api.BadRequestError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		case 401:
+			value := new(api.UnauthorizedError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		case 403:
+			value := new(api.ForbiddenError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		case 404:
+			value := new(api.NotFoundError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		}
+		return apiError
+	}
+
+	var response *api.DeleteTraceResponse
+	if err := core.DoRequest(
+		ctx,
+		c.httpClient,
+		endpointURL,
+		http.MethodDelete,
+		nil,
+		&response,
+		false,
+		c.header,
+		errorDecoder,
+	); err != nil {
+		return response, err
+	}
+	return response, nil
+}
+
+// Delete multiple traces
+func (c *Client) Deletemultiple(ctx context.Context, request *api.TraceDeleteMultipleRequest) (*api.DeleteTraceResponse, error) {
+	baseURL := ""
+	if c.baseURL != "" {
+		baseURL = c.baseURL
+	}
+	endpointURL := baseURL + "/" + "api/public/traces"
+
+	errorDecoder := func(statusCode int, body io.Reader) error {
+		raw, err := io.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		apiError := core.NewAPIError(statusCode, errors.New(string(raw)))
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		switch statusCode {
+		case 400:
+			value := new(api.BadRequestError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		case 401:
+			value := new(api.UnauthorizedError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		case 403:
+			value := new(api.ForbiddenError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		case 404:
+			value := new(api.NotFoundError)
+			value.APIError = apiError
+			if err := decoder.Decode(value); err != nil {
+				return apiError
+			}
+			return value
+		}
+		return apiError
+	}
+
+	var response *api.DeleteTraceResponse
+	if err := core.DoRequest(
+		ctx,
+		c.httpClient,
+		endpointURL,
+		http.MethodDelete,
+		request,
+		&response,
+		false,
+		c.header,
+		errorDecoder,
+	); err != nil {
+		return response, err
+	}
+	return response, nil
+}
@@ -14,6 +14,55 @@ type BaseEvent struct {
 	Metadata  interface{} `json:"metadata,omitempty"`
 }
 
+type Comment struct {
+	Id           string            `json:"id"`
+	ProjectId    string            `json:"projectId"`
+	CreatedAt    time.Time         `json:"createdAt"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
+	ObjectType   CommentObjectType `json:"objectType,omitempty"`
+	ObjectId     string            `json:"objectId"`
+	Content      string            `json:"content"`
+	AuthorUserId *string           `json:"authorUserId,omitempty"`
+}
+
+type CommentObjectType string
+
+const (
+	CommentObjectTypeTrace       CommentObjectType = "TRACE"
+	CommentObjectTypeObservation CommentObjectType = "OBSERVATION"
+	CommentObjectTypeSession     CommentObjectType = "SESSION"
+	CommentObjectTypePrompt      CommentObjectType = "PROMPT"
+)
+
+func NewCommentObjectTypeFromString(s string) (CommentObjectType, error) {
+	switch s {
+	case "TRACE":
+		return CommentObjectTypeTrace, nil
+	case "OBSERVATION":
+		return CommentObjectTypeObservation, nil
+	case "SESSION":
+		return CommentObjectTypeSession, nil
+	case "PROMPT":
+		return CommentObjectTypePrompt, nil
+	}
+	var t CommentObjectType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (c CommentObjectType) Ptr() *CommentObjectType {
+	return &c
+}
+
+type ConfigCategory struct {
+	Value float64 `json:"value"`
+	Label string  `json:"label"`
+}
+
+type CreateCommentResponse struct {
+	// The id of the created object in Langfuse
+	Id string `json:"id"`
+}
+
 type CreateEventBody struct {
 	TraceId             *string           `json:"traceId,omitempty"`
 	Name                *string           `json:"name,omitempty"`
@@ -92,6 +141,21 @@ type CreateSpanEvent struct {
 	Body      *CreateSpanBody `json:"body,omitempty"`
 }
 
+type DailyMetrics struct {
+	// A list of daily metrics, only days with ingested data are included.
+	Data []*DailyMetricsDetails `json:"data,omitempty"`
+	Meta *UtilsMetaResponse     `json:"meta,omitempty"`
+}
+
+type DailyMetricsDetails struct {
+	Date              string `json:"date"`
+	CountTraces       int    `json:"countTraces"`
+	CountObservations int    `json:"countObservations"`
+	// Total model cost in USD
+	TotalCost float64         `json:"totalCost"`
+	Usage     []*UsageByModel `json:"usage,omitempty"`
+}
+
 type Dataset struct {
 	Id        string         `json:"id"`
 	Name      string         `json:"name"`
@@ -107,6 +171,7 @@ type DatasetItem struct {
 	Status              DatasetStatus `json:"status,omitempty"`
 	Input               interface{}   `json:"input,omitempty"`
 	ExpectedOutput      *interface{}  `json:"expectedOutput,omitempty"`
+	SourceTraceId       *string       `json:"sourceTraceId,omitempty"`
 	SourceObservationId *string       `json:"sourceObservationId,omitempty"`
 	DatasetId           string        `json:"datasetId"`
 	CreatedAt           time.Time     `json:"createdAt"`
@@ -153,6 +218,45 @@ func (d DatasetStatus) Ptr() *DatasetStatus {
 	return &d
 }
 
+type DeleteDatasetItemResponse struct {
+	Message string `json:"message"`
+}
+
+type DeleteDatasetRunResponse struct {
+	Message string `json:"message"`
+}
+
+type DeleteTraceResponse struct {
+	Message string `json:"message"`
+}
+
+type GetCommentsResponse struct {
+	Data []*Comment         `json:"data,omitempty"`
+	Meta *UtilsMetaResponse `json:"meta,omitempty"`
+}
+
+type GetMediaResponse struct {
+	// The unique langfuse identifier of a media record
+	MediaId string `json:"mediaId"`
+	// The MIME type of the media record
+	ContentType string `json:"contentType"`
+	// The size of the media record in bytes
+	ContentLength int `json:"contentLength"`
+	// The date and time when the media record was uploaded
+	UploadedAt time.Time `json:"uploadedAt"`
+	// The download URL of the media record
+	Url string `json:"url"`
+	// The expiry date and time of the media record download URL
+	UrlExpiry string `json:"urlExpiry"`
+}
+
+type GetMediaUploadUrlResponse struct {
+	// The presigned upload URL. If the asset is already uploaded, this will be undefined
+	UploadUrl *string `json:"uploadUrl,omitempty"`
+	// The unique langfuse identifier of a media record
+	MediaId string `json:"mediaId"`
+}
+
 type HealthResponse struct {
 	// Langfuse server version
 	Version string `json:"version"`
@@ -167,11 +271,267 @@ type IngestionError struct {
 }
 
 type IngestionEvent struct {
-	Type      string                 `json:"type"`
-	Body      interface{}            `json:"body"`
-	ID        string                 `json:"id"`
-	Metadata  map[string]interface{} `json:"metadata"`
-	Timestamp time.Time              `json:"timestamp"`
+	Type              string
+	TraceCreate       *TraceEvent
+	ScoreCreate       *ScoreEvent
+	EventCreate       *CreateEventEvent
+	GenerationCreate  *CreateGenerationEvent
+	GenerationUpdate  *UpdateGenerationEvent
+	SpanCreate        *CreateSpanEvent
+	SpanUpdate        *UpdateSpanEvent
+	SdkLog            *SdkLogEvent
+	ObservationCreate *CreateObservationEvent
+	ObservationUpdate *UpdateObservationEvent
+}
+
+func NewIngestionEventFromTraceCreate(value *TraceEvent) *IngestionEvent {
+	return &IngestionEvent{Type: "trace-create", TraceCreate: value}
+}
+
+func NewIngestionEventFromScoreCreate(value *ScoreEvent) *IngestionEvent {
+	return &IngestionEvent{Type: "score-create", ScoreCreate: value}
+}
+
+func NewIngestionEventFromEventCreate(value *CreateEventEvent) *IngestionEvent {
+	return &IngestionEvent{Type: "event-create", EventCreate: value}
+}
+
+func NewIngestionEventFromGenerationCreate(value *CreateGenerationEvent) *IngestionEvent {
+	return &IngestionEvent{Type: "generation-create", GenerationCreate: value}
+}
+
+func NewIngestionEventFromGenerationUpdate(value *UpdateGenerationEvent) *IngestionEvent {
+	return &IngestionEvent{Type: "generation-update", GenerationUpdate: value}
+}
+
+func NewIngestionEventFromSpanCreate(value *CreateSpanEvent) *IngestionEvent {
+	return &IngestionEvent{Type: "span-create", SpanCreate: value}
+}
+
+func NewIngestionEventFromSpanUpdate(value *UpdateSpanEvent) *IngestionEvent {
+	return &IngestionEvent{Type: "span-update", SpanUpdate: value}
+}
+
+func NewIngestionEventFromSdkLog(value *SdkLogEvent) *IngestionEvent {
+	return &IngestionEvent{Type: "sdk-log", SdkLog: value}
+}
+
+func NewIngestionEventFromObservationCreate(value *CreateObservationEvent) *IngestionEvent {
+	return &IngestionEvent{Type: "observation-create", ObservationCreate: value}
+}
+
+func NewIngestionEventFromObservationUpdate(value *UpdateObservationEvent) *IngestionEvent {
+	return &IngestionEvent{Type: "observation-update", ObservationUpdate: value}
+}
+
+func (i *IngestionEvent) UnmarshalJSON(data []byte) error {
+	var unmarshaler struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &unmarshaler); err != nil {
+		return err
+	}
+	i.Type = unmarshaler.Type
+	switch unmarshaler.Type {
+	case "trace-create":
+		value := new(TraceEvent)
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		i.TraceCreate = value
+	case "score-create":
+		value := new(ScoreEvent)
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		i.ScoreCreate = value
+	case "event-create":
+		value := new(CreateEventEvent)
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		i.EventCreate = value
+	case "generation-create":
+		value := new(CreateGenerationEvent)
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		i.GenerationCreate = value
+	case "generation-update":
+		value := new(UpdateGenerationEvent)
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		i.GenerationUpdate = value
+	case "span-create":
+		value := new(CreateSpanEvent)
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		i.SpanCreate = value
+	case "span-update":
+		value := new(UpdateSpanEvent)
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		i.SpanUpdate = value
+	case "sdk-log":
+		value := new(SdkLogEvent)
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		i.SdkLog = value
+	case "observation-create":
+		value := new(CreateObservationEvent)
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		i.ObservationCreate = value
+	case "observation-update":
+		value := new(UpdateObservationEvent)
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		i.ObservationUpdate = value
+	}
+	return nil
+}
+
+func (i IngestionEvent) MarshalJSON() ([]byte, error) {
+	switch i.Type {
+	default:
+		return nil, fmt.Errorf("invalid type %s in %T", i.Type, i)
+	case "trace-create":
+		var marshaler = struct {
+			Type string `json:"type"`
+			*TraceEvent
+		}{
+			Type:       i.Type,
+			TraceEvent: i.TraceCreate,
+		}
+		return json.Marshal(marshaler)
+	case "score-create":
+		var marshaler = struct {
+			Type string `json:"type"`
+			*ScoreEvent
+		}{
+			Type:       i.Type,
+			ScoreEvent: i.ScoreCreate,
+		}
+		return json.Marshal(marshaler)
+	case "event-create":
+		var marshaler = struct {
+			Type string `json:"type"`
+			*CreateEventEvent
+		}{
+			Type:             i.Type,
+			CreateEventEvent: i.EventCreate,
+		}
+		return json.Marshal(marshaler)
+	case "generation-create":
+		var marshaler = struct {
+			Type string `json:"type"`
+			*CreateGenerationEvent
+		}{
+			Type:                  i.Type,
+			CreateGenerationEvent: i.GenerationCreate,
+		}
+		return json.Marshal(marshaler)
+	case "generation-update":
+		var marshaler = struct {
+			Type string `json:"type"`
+			*UpdateGenerationEvent
+		}{
+			Type:                  i.Type,
+			UpdateGenerationEvent: i.GenerationUpdate,
+		}
+		return json.Marshal(marshaler)
+	case "span-create":
+		var marshaler = struct {
+			Type string `json:"type"`
+			*CreateSpanEvent
+		}{
+			Type:            i.Type,
+			CreateSpanEvent: i.SpanCreate,
+		}
+		return json.Marshal(marshaler)
+	case "span-update":
+		var marshaler = struct {
+			Type string `json:"type"`
+			*UpdateSpanEvent
+		}{
+			Type:            i.Type,
+			UpdateSpanEvent: i.SpanUpdate,
+		}
+		return json.Marshal(marshaler)
+	case "sdk-log":
+		var marshaler = struct {
+			Type string `json:"type"`
+			*SdkLogEvent
+		}{
+			Type:        i.Type,
+			SdkLogEvent: i.SdkLog,
+		}
+		return json.Marshal(marshaler)
+	case "observation-create":
+		var marshaler = struct {
+			Type string `json:"type"`
+			*CreateObservationEvent
+		}{
+			Type:                   i.Type,
+			CreateObservationEvent: i.ObservationCreate,
+		}
+		return json.Marshal(marshaler)
+	case "observation-update":
+		var marshaler = struct {
+			Type string `json:"type"`
+			*UpdateObservationEvent
+		}{
+			Type:                   i.Type,
+			UpdateObservationEvent: i.ObservationUpdate,
+		}
+		return json.Marshal(marshaler)
+	}
+}
+
+type IngestionEventVisitor interface {
+	VisitTraceCreate(*TraceEvent) error
+	VisitScoreCreate(*ScoreEvent) error
+	VisitEventCreate(*CreateEventEvent) error
+	VisitGenerationCreate(*CreateGenerationEvent) error
+	VisitGenerationUpdate(*UpdateGenerationEvent) error
+	VisitSpanCreate(*CreateSpanEvent) error
+	VisitSpanUpdate(*UpdateSpanEvent) error
+	VisitSdkLog(*SdkLogEvent) error
+	VisitObservationCreate(*CreateObservationEvent) error
+	VisitObservationUpdate(*UpdateObservationEvent) error
+}
+
+func (i *IngestionEvent) Accept(visitor IngestionEventVisitor) error {
+	switch i.Type {
+	default:
+		return fmt.Errorf("invalid type %s in %T", i.Type, i)
+	case "trace-create":
+		return visitor.VisitTraceCreate(i.TraceCreate)
+	case "score-create":
+		return visitor.VisitScoreCreate(i.ScoreCreate)
+	case "event-create":
+		return visitor.VisitEventCreate(i.EventCreate)
+	case "generation-create":
+		return visitor.VisitGenerationCreate(i.GenerationCreate)
+	case "generation-update":
+		return visitor.VisitGenerationUpdate(i.GenerationUpdate)
+	case "span-create":
+		return visitor.VisitSpanCreate(i.SpanCreate)
+	case "span-update":
+		return visitor.VisitSpanUpdate(i.SpanUpdate)
+	case "sdk-log":
+		return visitor.VisitSdkLog(i.SdkLog)
+	case "observation-create":
+		return visitor.VisitObservationCreate(i.ObservationCreate)
+	case "observation-update":
+		return visitor.VisitObservationUpdate(i.ObservationUpdate)
+	}
 }
 
 type IngestionResponse struct {
@@ -245,7 +605,9 @@ type MapValue struct {
 	typeName        string
 	StringOptional  *string
 	IntegerOptional *int
+	DoubleOptional  *float64
 	BooleanOptional *bool
+	StringList      []string
 }
 
 func NewMapValueFromStringOptional(value *string) *MapValue {
@@ -256,10 +618,18 @@ func NewMapValueFromIntegerOptional(value *int) *MapValue {
 	return &MapValue{typeName: "integerOptional", IntegerOptional: value}
 }
 
+func NewMapValueFromDoubleOptional(value *float64) *MapValue {
+	return &MapValue{typeName: "doubleOptional", DoubleOptional: value}
+}
+
 func NewMapValueFromBooleanOptional(value *bool) *MapValue {
 	return &MapValue{typeName: "booleanOptional", BooleanOptional: value}
 }
 
+func NewMapValueFromStringList(value []string) *MapValue {
+	return &MapValue{typeName: "stringList", StringList: value}
+}
+
 func (m *MapValue) UnmarshalJSON(data []byte) error {
 	var valueStringOptional *string
 	if err := json.Unmarshal(data, &valueStringOptional); err == nil {
@@ -273,12 +643,24 @@ func (m *MapValue) UnmarshalJSON(data []byte) error {
 		m.IntegerOptional = valueIntegerOptional
 		return nil
 	}
+	var valueDoubleOptional *float64
+	if err := json.Unmarshal(data, &valueDoubleOptional); err == nil {
+		m.typeName = "doubleOptional"
+		m.DoubleOptional = valueDoubleOptional
+		return nil
+	}
 	var valueBooleanOptional *bool
 	if err := json.Unmarshal(data, &valueBooleanOptional); err == nil {
 		m.typeName = "booleanOptional"
 		m.BooleanOptional = valueBooleanOptional
 		return nil
 	}
+	var valueStringList []string
+	if err := json.Unmarshal(data, &valueStringList); err == nil {
+		m.typeName = "stringList"
+		m.StringList = valueStringList
+		return nil
+	}
 	return fmt.Errorf("%s cannot be deserialized as a %T", data, m)
 }
 
@@ -290,15 +672,21 @@ func (m MapValue) MarshalJSON() ([]byte, error) {
 		return json.Marshal(m.StringOptional)
 	case "integerOptional":
 		return json.Marshal(m.IntegerOptional)
+	case "doubleOptional":
+		return json.Marshal(m.DoubleOptional)
 	case "booleanOptional":
 		return json.Marshal(m.BooleanOptional)
+	case "stringList":
+		return json.Marshal(m.StringList)
 	}
 }
 
 type MapValueVisitor interface {
 	VisitStringOptional(*string) error
 	VisitIntegerOptional(*int) error
+	VisitDoubleOptional(*float64) error
 	VisitBooleanOptional(*bool) error
+	VisitStringList([]string) error
 }
 
 func (m *MapValue) Accept(visitor MapValueVisitor) error {
@@ -309,9 +697,138 @@ func (m *MapValue) Accept(visitor MapValueVisitor) error {
 		return visitor.VisitStringOptional(m.StringOptional)
 	case "integerOptional":
 		return visitor.VisitIntegerOptional(m.IntegerOptional)
+	case "doubleOptional":
+		return visitor.VisitDoubleOptional(m.DoubleOptional)
 	case "booleanOptional":
 		return visitor.VisitBooleanOptional(m.BooleanOptional)
+	case "stringList":
+		return visitor.VisitStringList(m.StringList)
+	}
+}
+
+type MediaContentType string
+
+const (
+	MediaContentTypeImagePng               MediaContentType = "image/png"
+	MediaContentTypeImageJpeg              MediaContentType = "image/jpeg"
+	MediaContentTypeImageJpg               MediaContentType = "image/jpg"
+	MediaContentTypeImageWebp              MediaContentType = "image/webp"
+	MediaContentTypeImageGif               MediaContentType = "image/gif"
+	MediaContentTypeImageSvgXml            MediaContentType = "image/svg+xml"
+	MediaContentTypeImageTiff              MediaContentType = "image/tiff"
+	MediaContentTypeImageBmp               MediaContentType = "image/bmp"
+	MediaContentTypeAudioMpeg              MediaContentType = "audio/mpeg"
+	MediaContentTypeAudioMp3               MediaContentType = "audio/mp3"
+	MediaContentTypeAudioWav               MediaContentType = "audio/wav"
+	MediaContentTypeAudioOgg               MediaContentType = "audio/ogg"
+	MediaContentTypeAudioOga               MediaContentType = "audio/oga"
+	MediaContentTypeAudioAac               MediaContentType = "audio/aac"
+	MediaContentTypeAudioMp4               MediaContentType = "audio/mp4"
+	MediaContentTypeAudioFlac              MediaContentType = "audio/flac"
+	MediaContentTypeVideoMp4               MediaContentType = "video/mp4"
+	MediaContentTypeVideoWebm              MediaContentType = "video/webm"
+	MediaContentTypeTextPlain              MediaContentType = "text/plain"
+	MediaContentTypeTextHtml               MediaContentType = "text/html"
+	MediaContentTypeTextCss                MediaContentType = "text/css"
+	MediaContentTypeTextCsv                MediaContentType = "text/csv"
+	MediaContentTypeApplicationPdf         MediaContentType = "application/pdf"
+	MediaContentTypeApplicationMsword      MediaContentType = "application/msword"
+	MediaContentTypeApplicationVndMsExcel  MediaContentType = "application/vnd.ms-excel"
+	MediaContentTypeApplicationZip         MediaContentType = "application/zip"
+	MediaContentTypeApplicationJson        MediaContentType = "application/json"
+	MediaContentTypeApplicationXml         MediaContentType = "application/xml"
+	MediaContentTypeApplicationOctetStream MediaContentType = "application/octet-stream"
+)
+
+func NewMediaContentTypeFromString(s string) (MediaContentType, error) {
+	switch s {
+	case "image/png":
+		return MediaContentTypeImagePng, nil
+	case "image/jpeg":
+		return MediaContentTypeImageJpeg, nil
+	case "image/jpg":
+		return MediaContentTypeImageJpg, nil
+	case "image/webp":
+		return MediaContentTypeImageWebp, nil
+	case "image/gif":
+		return MediaContentTypeImageGif, nil
+	case "image/svg+xml":
+		return MediaContentTypeImageSvgXml, nil
+	case "image/tiff":
+		return MediaContentTypeImageTiff, nil
+	case "image/bmp":
+		return MediaContentTypeImageBmp, nil
+	case "audio/mpeg":
+		return MediaContentTypeAudioMpeg, nil
+	case "audio/mp3":
+		return MediaContentTypeAudioMp3, nil
+	case "audio/wav":
+		return MediaContentTypeAudioWav, nil
+	case "audio/ogg":
+		return MediaContentTypeAudioOgg, nil
+	case "audio/oga":
+		return MediaContentTypeAudioOga, nil
+	case "audio/aac":
+		return MediaContentTypeAudioAac, nil
+	case "audio/mp4":
+		return MediaContentTypeAudioMp4, nil
+	case "audio/flac":
+		return MediaContentTypeAudioFlac, nil
+	case "video/mp4":
+		return MediaContentTypeVideoMp4, nil
+	case "video/webm":
+		return MediaContentTypeVideoWebm, nil
+	case "text/plain":
+		return MediaContentTypeTextPlain, nil
+	case "text/html":
+		return MediaContentTypeTextHtml, nil
+	case "text/css":
+		return MediaContentTypeTextCss, nil
+	case "text/csv":
+		return MediaContentTypeTextCsv, nil
+	case "application/pdf":
+		return MediaContentTypeApplicationPdf, nil
+	case "application/msword":
+		return MediaContentTypeApplicationMsword, nil
+	case "application/vnd.ms-excel":
+		return MediaContentTypeApplicationVndMsExcel, nil
+	case "application/zip":
+		return MediaContentTypeApplicationZip, nil
+	case "application/json":
+		return MediaContentTypeApplicationJson, nil
+	case "application/xml":
+		return MediaContentTypeApplicationXml, nil
+	case "application/octet-stream":
+		return MediaContentTypeApplicationOctetStream, nil
 	}
+	var t MediaContentType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (m MediaContentType) Ptr() *MediaContentType {
+	return &m
+}
+
+type Model struct {
+	Id string `json:"id"`
+	// Name of the model definition. If multiple with the same name exist, they are applied in the following order: (1) custom over built-in, (2) newest according to startTime where model.startTime<observation.startTime
+	ModelName string `json:"modelName"`
+	// Regex pattern which matches this model definition to generation.model. Useful in case of fine-tuned models. If you want to exact match, use `(?i)^modelname$`
+	MatchPattern string `json:"matchPattern"`
+	// Apply only to generations which are newer than this ISO date.
+	StartDate *time.Time     `json:"startDate,omitempty"`
+	Unit      ModelUsageUnit `json:"unit,omitempty"`
+	// Price (USD) per input unit
+	InputPrice *float64 `json:"inputPrice,omitempty"`
+	// Price (USD) per output unit
+	OutputPrice *float64 `json:"outputPrice,omitempty"`
+	// Price (USD) per total unit. Cannot be set if input or output price is set.
+	TotalPrice *float64 `json:"totalPrice,omitempty"`
+	// Optional. Tokenizer to be applied to observations which match to this model. See docs for more details.
+	TokenizerId *string `json:"tokenizerId,omitempty"`
+	// Optional. Configuration for the selected tokenizer. Needs to be JSON. See docs for more details.
+	TokenizerConfig   interface{} `json:"tokenizerConfig,omitempty"`
+	IsLangfuseManaged bool        `json:"isLangfuseManaged"`
 }
 
 type ModelUsageUnit string
@@ -454,6 +971,42 @@ type OptionalObservationBody struct {
 	Version             *string           `json:"version,omitempty"`
 }
 
+type PaginatedDatasetItems struct {
+	Data []*DatasetItem     `json:"data,omitempty"`
+	Meta *UtilsMetaResponse `json:"meta,omitempty"`
+}
+
+type PaginatedDatasetRuns struct {
+	Data []*DatasetRun      `json:"data,omitempty"`
+	Meta *UtilsMetaResponse `json:"meta,omitempty"`
+}
+
+type PaginatedDatasets struct {
+	Data []*Dataset         `json:"data,omitempty"`
+	Meta *UtilsMetaResponse `json:"meta,omitempty"`
+}
+
+type PaginatedModels struct {
+	Data []*Model           `json:"data,omitempty"`
+	Meta *UtilsMetaResponse `json:"meta,omitempty"`
+}
+
+type PaginatedSessions struct {
+	Data []*Session         `json:"data,omitempty"`
+	Meta *UtilsMetaResponse `json:"meta,omitempty"`
+}
+
+type PatchMediaBody struct {
+	// The date and time when the media record was uploaded
+	UploadedAt time.Time `json:"uploadedAt"`
+	// The HTTP status code of the upload
+	UploadHttpStatus int `json:"uploadHttpStatus"`
+	// The HTTP error message of the upload
+	UploadHttpError *string `json:"uploadHttpError,omitempty"`
+	// The time in milliseconds it took to upload the media record
+	UploadTimeMs *int `json:"uploadTimeMs,omitempty"`
+}
+
 type Project struct {
 	Id   string `json:"id"`
 	Name string `json:"name"`
@@ -470,22 +1023,134 @@ type Prompt struct {
 }
 
 type Score struct {
-	Id            string    `json:"id"`
-	TraceId       string    `json:"traceId"`
-	Name          string    `json:"name"`
-	Value         float64   `json:"value"`
-	ObservationId *string   `json:"observationId,omitempty"`
-	Timestamp     time.Time `json:"timestamp"`
-	Comment       *string   `json:"comment,omitempty"`
+	Id      string  `json:"id"`
+	TraceId string  `json:"traceId"`
+	Name    string  `json:"name"`
+	Value   float64 `json:"value"`
+	// The string representation of the score value. For categorical scores this is the category
+	StringValue   *string       `json:"stringValue,omitempty"`
+	DataType      ScoreDataType `json:"dataType,omitempty"`
+	ObservationId *string       `json:"observationId,omitempty"`
+	Timestamp     time.Time     `json:"timestamp"`
+	Comment       *string       `json:"comment,omitempty"`
 }
 
 type ScoreBody struct {
-	Id            *string `json:"id,omitempty"`
-	TraceId       string  `json:"traceId"`
-	Name          string  `json:"name"`
-	Value         float64 `json:"value"`
-	ObservationId *string `json:"observationId,omitempty"`
-	Comment       *string `json:"comment,omitempty"`
+	Id      *string `json:"id,omitempty"`
+	TraceId string  `json:"traceId"`
+	Name    string  `json:"name"`
+	// The value of the score. Must be passed as string for categorical scores, and numeric for boolean and numeric scores
+	Value         *CreateScoreValue `json:"value"`
+	ObservationId *string           `json:"observationId,omitempty"`
+	Comment       *string           `json:"comment,omitempty"`
+	// When set, must match the score value's type. If not set, will be inferred from the score value
+	DataType *ScoreDataType `json:"dataType,omitempty"`
+}
+
+// Configuration for a score
+type ScoreConfig struct {
+	Id        string        `json:"id"`
+	Name      string        `json:"name"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+	ProjectId string        `json:"projectId"`
+	DataType  ScoreDataType `json:"dataType,omitempty"`
+	// Whether the score config is archived. Defaults to false
+	IsArchived bool `json:"isArchived"`
+	// Sets minimum value for numerical scores. If not set, the minimum value defaults to -∞
+	MinValue *float64 `json:"minValue,omitempty"`
+	// Sets maximum value for numerical scores. If not set, the maximum value defaults to +∞
+	MaxValue *float64 `json:"maxValue,omitempty"`
+	// Configures custom categories for categorical scores
+	Categories  []*ConfigCategory `json:"categories,omitempty"`
+	Description *string           `json:"description,omitempty"`
+}
+
+type ScoreConfigs struct {
+	Data []*ScoreConfig     `json:"data,omitempty"`
+	Meta *UtilsMetaResponse `json:"meta,omitempty"`
+}
+
+type ScoreDataType string
+
+const (
+	ScoreDataTypeNumeric     ScoreDataType = "NUMERIC"
+	ScoreDataTypeBoolean     ScoreDataType = "BOOLEAN"
+	ScoreDataTypeCategorical ScoreDataType = "CATEGORICAL"
+)
+
+func NewScoreDataTypeFromString(s string) (ScoreDataType, error) {
+	switch s {
+	case "NUMERIC":
+		return ScoreDataTypeNumeric, nil
+	case "BOOLEAN":
+		return ScoreDataTypeBoolean, nil
+	case "CATEGORICAL":
+		return ScoreDataTypeCategorical, nil
+	}
+	var t ScoreDataType
+	return "", fmt.Errorf("%s is not a valid %T", s, t)
+}
+
+func (s ScoreDataType) Ptr() *ScoreDataType {
+	return &s
+}
+
+type CreateScoreValue struct {
+	typeName string
+	Double   float64
+	String   string
+}
+
+func NewCreateScoreValueFromDouble(value float64) *CreateScoreValue {
+	return &CreateScoreValue{typeName: "double", Double: value}
+}
+
+func NewCreateScoreValueFromString(value string) *CreateScoreValue {
+	return &CreateScoreValue{typeName: "string", String: value}
+}
+
+func (c *CreateScoreValue) UnmarshalJSON(data []byte) error {
+	var valueDouble float64
+	if err := json.Unmarshal(data, &valueDouble); err == nil {
+		c.typeName = "double"
+		c.Double = valueDouble
+		return nil
+	}
+	var valueString string
+	if err := json.Unmarshal(data, &valueString); err == nil {
+		c.typeName = "string"
+		c.String = valueString
+		return nil
+	}
+	return fmt.Errorf("%s cannot be deserialized as a %T", data, c)
+}
+
+func (c CreateScoreValue) MarshalJSON() ([]byte, error) {
+	switch c.typeName {
+	default:
+		return nil, fmt.Errorf("invalid type %s in %T", c.typeName, c)
+	case "double":
+		return json.Marshal(c.Double)
+	case "string":
+		return json.Marshal(c.String)
+	}
+}
+
+type CreateScoreValueVisitor interface {
+	VisitDouble(float64) error
+	VisitString(string) error
+}
+
+func (c *CreateScoreValue) Accept(visitor CreateScoreValueVisitor) error {
+	switch c.typeName {
+	default:
+		return fmt.Errorf("invalid type %s in %T", c.typeName, c)
+	case "double":
+		return visitor.VisitDouble(c.Double)
+	case "string":
+		return visitor.VisitString(c.String)
+	}
 }
 
 type ScoreEvent struct {
@@ -582,6 +1247,10 @@ type TraceWithDetails struct {
 	Observations []string `json:"observations,omitempty"`
 	// List of score ids
 	Scores []string `json:"scores,omitempty"`
+	// Latency of trace in seconds
+	Latency *float64 `json:"latency,omitempty"`
+	// Cost of trace in USD
+	TotalCost *float64 `json:"totalCost,omitempty"`
 }
 
 type TraceWithFullDetails struct {
@@ -684,6 +1353,27 @@ type Usage struct {
 	Output *int            `json:"output,omitempty"`
 	Total  *int            `json:"total,omitempty"`
 	Unit   *ModelUsageUnit `json:"unit,omitempty"`
+	// USD input cost
+	InputCost *float64 `json:"inputCost,omitempty"`
+	// USD output cost
+	OutputCost *float64 `json:"outputCost,omitempty"`
+	// USD total cost, defaults to input+output
+	TotalCost *float64 `json:"totalCost,omitempty"`
+}
+
+// Daily usage of a given model. Usage corresponds to the unit set for the specific model (e.g. tokens).
+type UsageByModel struct {
+	Model *string `json:"model,omitempty"`
+	// Total number of generation input units (e.g. tokens)
+	InputUsage int `json:"inputUsage"`
+	// Total number of generation output units (e.g. tokens)
+	OutputUsage int `json:"outputUsage"`
+	// Total number of generation total units (e.g. tokens)
+	TotalUsage        int `json:"totalUsage"`
+	CountTraces       int `json:"countTraces"`
+	CountObservations int `json:"countObservations"`
+	// Total model cost in USD
+	TotalCost float64 `json:"totalCost"`
 }
 
 type UtilsMetaResponse struct {
@@ -0,0 +1,104 @@
+// Command langfuse-export exports traces with their observations and scores to jsonl, csv or parquet.
+//
+// Credentials are read from LANGFUSE_PUBLIC_KEY, LANGFUSE_SECRET_KEY and LANGFUSE_HOST. When -checkpoint is set only
+// traces newer than the previous run are exported, which makes it suitable for nightly jobs
+//
+//	langfuse-export -format csv -out traces.csv -checkpoint .langfuse-export
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/wepala/langfuse-go/api"
+	"github.com/wepala/langfuse-go/export"
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func main() {
+	format := flag.String("format", "jsonl", "output format, jsonl, csv or parquet")
+	out := flag.String("out", "", "file to write to, defaults to stdout")
+	checkpoint := flag.String("checkpoint", "", "file used to remember the newest exported trace between runs")
+	since := flag.String("since", "", "only export traces at or after this time (RFC3339), overrides the checkpoint")
+	until := flag.String("until", "", "only export traces before this time (RFC3339)")
+	name := flag.String("name", "", "only export traces with this name")
+	userID := flag.String("user", "", "only export traces for this user")
+	sessionID := flag.String("session", "", "only export traces in this session")
+	release := flag.String("release", "", "only export traces from this release")
+	concurrency := flag.Int("concurrency", 4, "number of traces fetched concurrently")
+	flag.Parse()
+
+	if err := run(*format, *out, *checkpoint, *since, *until, *concurrency, &api.TraceListRequest{
+		Name:      optional(*name),
+		UserId:    optional(*userID),
+		SessionId: optional(*sessionID),
+		Release:   optional(*release),
+	}); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(format, out, checkpoint, since, until string, concurrency int, request *api.TraceListRequest) error {
+	ctxt, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	options := export.Options{
+		Request:     request,
+		Concurrency: concurrency,
+	}
+	var err error
+	if since != "" {
+		if options.Since, err = time.Parse(time.RFC3339, since); err != nil {
+			return fmt.Errorf("invalid since: %w", err)
+		}
+	} else if checkpoint != "" {
+		if options.Checkpoint, err = export.LoadCheckpoint(checkpoint); err != nil {
+			return fmt.Errorf("error loading checkpoint: %w", err)
+		}
+	}
+	if until != "" {
+		if options.Until, err = time.Parse(time.RFC3339, until); err != nil {
+			return fmt.Errorf("invalid until: %w", err)
+		}
+	}
+
+	var output io.Writer = os.Stdout
+	if out != "" {
+		file, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		output = file
+	}
+	writer, err := export.NewWriter(format, output)
+	if err != nil {
+		return err
+	}
+
+	sdk := langfuse.New(ctxt, langfuse.Options{})
+	result, err := export.New(sdk, writer, options).Export(ctxt)
+	if result != nil {
+		log.Printf("exported %d traces (%d rows)", result.Traces, result.Rows)
+	}
+	if err != nil {
+		return err
+	}
+	if checkpoint != "" {
+		return export.SaveCheckpoint(checkpoint, result.Checkpoint)
+	}
+	return nil
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+// Checkpoint records where an export ended. The next export starts Overlap before Timestamp to pick up traces that
+// were ingested late and skips TraceIDs, the traces within the overlap that were already exported
+type Checkpoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	TraceIDs  []string  `json:"traceIds,omitempty"`
+}
+
+// LoadCheckpoint reads the checkpoint saved by a previous export. A missing file returns an empty checkpoint so the
+// first run exports everything
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Checkpoint{}, nil
+		}
+		return nil, err
+	}
+	value := strings.TrimSpace(string(content))
+	if value == "" {
+		return &Checkpoint{}, nil
+	}
+	//checkpoints used to be stored as a bare timestamp
+	if !strings.HasPrefix(value, "{") {
+		timestamp, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return nil, err
+		}
+		return &Checkpoint{Timestamp: timestamp}, nil
+	}
+	checkpoint := &Checkpoint{}
+	if err = json.Unmarshal([]byte(value), checkpoint); err != nil {
+		return nil, err
+	}
+	return checkpoint, nil
+}
+
+// SaveCheckpoint stores the checkpoint so the next export only fetches newer traces
+func SaveCheckpoint(path string, checkpoint *Checkpoint) error {
+	if checkpoint == nil || checkpoint.Timestamp.IsZero() {
+		return nil
+	}
+	content, err := json.Marshal(&Checkpoint{Timestamp: checkpoint.Timestamp.UTC(), TraceIDs: checkpoint.TraceIDs})
+	if err != nil {
+		return err
+	}
+	//write to a temporary file first so an interrupted run doesn't leave a corrupt checkpoint
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(content, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
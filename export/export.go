@@ -0,0 +1,208 @@
+// Package export copies traces with their observations and scores out of Langfuse into flat files for offline analysis
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/wepala/langfuse-go/api"
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+// DefaultOverlap is how far before a checkpoint the next export starts looking for traces that were ingested late
+const DefaultOverlap = 5 * time.Minute
+
+type Options struct {
+	// Request filters the traces that are exported. The page and timestamp filters are managed by the exporter
+	Request *api.TraceListRequest
+	// Since only exports traces with a timestamp at or after this time
+	Since time.Time
+	// Checkpoint resumes from a previous export. Traces from Overlap before the checkpoint are listed again and the
+	// ones the previous export already wrote are skipped. It takes precedence over Since
+	Checkpoint *Checkpoint
+	// Overlap defaults to DefaultOverlap
+	Overlap time.Duration
+	// Until only exports traces with a timestamp before this time
+	Until time.Time
+	// Concurrency is the number of traces whose details are fetched at the same time. Defaults to 4
+	Concurrency int
+	// PageSize is the number of traces requested per page
+	PageSize int
+}
+
+type Result struct {
+	Traces int
+	Rows   int
+	// Checkpoint is the newest trace exported with the traces in the overlap before it. Pass it as the Checkpoint of
+	// the next export
+	Checkpoint *Checkpoint
+}
+
+type Exporter struct {
+	sdk     *langfuse.LangFuse
+	writer  Writer
+	options Options
+}
+
+func New(sdk *langfuse.LangFuse, writer Writer, options Options) *Exporter {
+	if options.Concurrency <= 0 {
+		options.Concurrency = 4
+	}
+	if options.Overlap <= 0 {
+		options.Overlap = DefaultOverlap
+	}
+	return &Exporter{
+		sdk:     sdk,
+		writer:  writer,
+		options: options,
+	}
+}
+
+type fetchResult struct {
+	id        string
+	timestamp time.Time
+	trace     *api.TraceWithFullDetails
+	//skipped is set for traces that were written by the previous export
+	skipped bool
+	err     error
+}
+
+// Export writes every matching trace in chronological order. Rows are flushed to the writer as each trace is fetched
+// so memory use doesn't grow with the size of the export
+func (e *Exporter) Export(ctxt context.Context) (*Result, error) {
+	if e.sdk == nil || e.writer == nil {
+		return nil, errors.New("sdk and writer are required")
+	}
+	tctxt, cancel := context.WithCancel(ctxt)
+	defer cancel()
+
+	request := api.TraceListRequest{}
+	if e.options.Request != nil {
+		request = *e.options.Request
+	}
+	request.Page = nil
+	request.OrderBy = api.String("timestamp.asc")
+	since := e.options.Since
+	skip := make(map[string]bool)
+	if e.options.Checkpoint != nil && !e.options.Checkpoint.Timestamp.IsZero() {
+		since = e.options.Checkpoint.Timestamp.Add(-e.options.Overlap)
+		for _, id := range e.options.Checkpoint.TraceIDs {
+			skip[id] = true
+		}
+	}
+	if !since.IsZero() {
+		request.FromTimestamp = api.Time(since)
+	}
+	if !e.options.Until.IsZero() {
+		request.ToTimestamp = api.Time(e.options.Until)
+	}
+	if e.options.PageSize > 0 {
+		request.Limit = api.Int(e.options.PageSize)
+	}
+
+	results := make(chan chan fetchResult, e.options.Concurrency)
+	go e.fetch(tctxt, &request, skip, results)
+
+	result := &Result{}
+	checkpoint := since
+	if e.options.Checkpoint != nil {
+		checkpoint = e.options.Checkpoint.Timestamp
+	}
+	//seen holds the timestamp of every trace that is now exported, including the ones skipped in the overlap
+	seen := make(map[string]time.Time)
+	var err error
+	for pending := range results {
+		fetched := <-pending
+		if err != nil {
+			continue
+		}
+		if fetched.err != nil {
+			err = fetched.err
+			cancel()
+			continue
+		}
+		if fetched.skipped {
+			seen[fetched.id] = fetched.timestamp
+			continue
+		}
+		if fetched.trace == nil {
+			continue
+		}
+		for _, row := range Rows(fetched.trace) {
+			if err = e.writer.Write(row); err != nil {
+				break
+			}
+			result.Rows++
+		}
+		if err != nil {
+			cancel()
+			continue
+		}
+		result.Traces++
+		seen[fetched.trace.Id] = fetched.trace.Timestamp
+	}
+	result.Checkpoint = newCheckpoint(checkpoint, seen, e.options.Overlap)
+	if closeErr := e.writer.Close(); err == nil {
+		err = closeErr
+	}
+	return result, err
+}
+
+// fetch lists the traces and retrieves their details concurrently. Results are queued in list order so the export
+// stays chronological
+func (e *Exporter) fetch(ctxt context.Context, request *api.TraceListRequest, skip map[string]bool, results chan<- chan fetchResult) {
+	defer close(results)
+	it := e.sdk.ListTraces(ctxt, request, &langfuse.IteratorOptions{Prefetch: 1})
+	defer it.Close()
+	for it.Next() {
+		trace := it.Current()
+		pending := make(chan fetchResult, 1)
+		if skip[trace.Id] {
+			pending <- fetchResult{id: trace.Id, timestamp: trace.Timestamp, skipped: true}
+		}
+		select {
+		case results <- pending:
+		case <-ctxt.Done():
+			return
+		}
+		if skip[trace.Id] {
+			continue
+		}
+		go func(traceID string) {
+			details, err := e.sdk.Client().Trace.Get(ctxt, traceID)
+			if err != nil {
+				err = fmt.Errorf("error retrieving trace %s: %w", traceID, err)
+			}
+			pending <- fetchResult{trace: details, err: err}
+		}(trace.Id)
+	}
+	if err := it.Err(); err != nil {
+		pending := make(chan fetchResult, 1)
+		pending <- fetchResult{err: fmt.Errorf("error listing traces: %w", err)}
+		select {
+		case results <- pending:
+		case <-ctxt.Done():
+		}
+	}
+}
+
+// newCheckpoint moves the checkpoint to the newest exported trace and remembers the traces within the overlap so the
+// next export doesn't write them again
+func newCheckpoint(previous time.Time, seen map[string]time.Time, overlap time.Duration) *Checkpoint {
+	checkpoint := &Checkpoint{Timestamp: previous}
+	for _, timestamp := range seen {
+		if timestamp.After(checkpoint.Timestamp) {
+			checkpoint.Timestamp = timestamp
+		}
+	}
+	for id, timestamp := range seen {
+		if !timestamp.Before(checkpoint.Timestamp.Add(-overlap)) {
+			checkpoint.TraceIDs = append(checkpoint.TraceIDs, id)
+		}
+	}
+	sort.Strings(checkpoint.TraceIDs)
+	return checkpoint
+}
@@ -0,0 +1,251 @@
+package export_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wepala/langfuse-go/export"
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func newServer(t *testing.T, timestamps []string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if req.URL.Path == "/api/public/traces" {
+			if req.URL.Query().Get("orderBy") != "timestamp.asc" {
+				t.Errorf("expected traces to be ordered by timestamp")
+			}
+			var data []map[string]interface{}
+			for i, timestamp := range timestamps {
+				data = append(data, map[string]interface{}{"id": fmt.Sprintf("trace-%d", i), "timestamp": timestamp})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": data,
+				"meta": map[string]interface{}{"page": 1, "limit": 50, "totalItems": len(data), "totalPages": 1},
+			})
+			return
+		}
+		id := strings.TrimPrefix(req.URL.Path, "/api/public/traces/")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":        id,
+			"timestamp": timestamps[int(id[len(id)-1]-'0')],
+			"name":      "chat",
+			"userId":    "user-id",
+			"input":     map[string]interface{}{"question": "hi"},
+			"observations": []map[string]interface{}{{
+				"id":        id + "-generation",
+				"traceId":   id,
+				"type":      "GENERATION",
+				"startTime": "2024-01-01T00:00:00Z",
+				"endTime":   "2024-01-01T00:00:01.5Z",
+				"model":     "gpt-4",
+				"usage":     map[string]interface{}{"input": 10, "output": 5, "total": 15},
+			}},
+			"scores": []map[string]interface{}{{
+				"id": id + "-score", "traceId": id, "name": "quality", "value": 0.87, "timestamp": "2024-01-01T00:00:02Z",
+			}},
+		})
+	}))
+}
+
+func TestExporter_Export(t *testing.T) {
+	t.Run("should export traces, observations and scores as flattened rows", func(t *testing.T) {
+		server := newServer(t, []string{"2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z"})
+		defer server.Close()
+		sdk := langfuse.New(context.TODO(), langfuse.Options{Host: server.URL, HttpClient: server.Client()})
+
+		var buf bytes.Buffer
+		result, err := export.New(sdk, export.NewJSONLWriter(&buf), export.Options{}).Export(context.TODO())
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if result.Traces != 2 || result.Rows != 6 {
+			t.Errorf("expected %d traces and %d rows, got %d and %d", 2, 6, result.Traces, result.Rows)
+		}
+		if !result.Checkpoint.Timestamp.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("expected checkpoint to be the newest trace, got %s", result.Checkpoint.Timestamp)
+		}
+		if strings.Join(result.Checkpoint.TraceIDs, ",") != "trace-1" {
+			t.Errorf("expected only the trace within the overlap to be remembered, got %v", result.Checkpoint.TraceIDs)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		var row export.Row
+		if err = json.Unmarshal([]byte(lines[1]), &row); err != nil {
+			t.Fatalf("expected row to be valid json, got %s", err)
+		}
+		if row.RecordType != export.RecordTypeObservation || row.TraceID != "trace-0" || row.UserID != "user-id" {
+			t.Errorf("expected observation row with trace columns, got %+v", row)
+		}
+		if row.LatencyMs == nil || *row.LatencyMs != 1500 {
+			t.Errorf("expected latency to be calculated")
+		}
+		if row.TotalTokens == nil || *row.TotalTokens != 15 {
+			t.Errorf("expected usage to be flattened")
+		}
+	})
+	t.Run("should skip traces that were exported by the previous run", func(t *testing.T) {
+		server := newServer(t, []string{"2024-01-01T00:00:00Z", "2024-01-02T00:00:00Z"})
+		defer server.Close()
+		sdk := langfuse.New(context.TODO(), langfuse.Options{Host: server.URL, HttpClient: server.Client()})
+
+		var buf bytes.Buffer
+		result, err := export.New(sdk, export.NewCSVWriter(&buf), export.Options{
+			Checkpoint: &export.Checkpoint{Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), TraceIDs: []string{"trace-0"}},
+		}).Export(context.TODO())
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if result.Traces != 1 {
+			t.Errorf("expected %d trace to be exported, got %d", 1, result.Traces)
+		}
+		records, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("expected valid csv, got %s", err)
+		}
+		if len(records) != 4 {
+			t.Fatalf("expected header and %d rows, got %d records", 3, len(records))
+		}
+		if strings.Join(records[0], ",") != strings.Join(export.Columns, ",") {
+			t.Errorf("expected header to match the columns")
+		}
+		if records[1][1] != "trace-1" {
+			t.Errorf("expected only the newer trace to be exported, got %s", records[1][1])
+		}
+	})
+	t.Run("should export late and same timestamp traces that the previous run didn't write", func(t *testing.T) {
+		server := newServer(t, []string{"2024-01-01T11:58:00Z", "2024-01-01T12:00:00Z", "2024-01-01T12:00:00Z"})
+		defer server.Close()
+		sdk := langfuse.New(context.TODO(), langfuse.Options{Host: server.URL, HttpClient: server.Client()})
+
+		checkpoint := &export.Checkpoint{Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), TraceIDs: []string{"trace-1"}}
+		var buf bytes.Buffer
+		result, err := export.New(sdk, export.NewJSONLWriter(&buf), export.Options{Checkpoint: checkpoint}).Export(context.TODO())
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if result.Traces != 2 {
+			t.Errorf("expected %d traces to be exported, got %d", 2, result.Traces)
+		}
+		if strings.Contains(buf.String(), `"trace_id":"trace-1"`) {
+			t.Errorf("expected the trace from the previous run to be skipped")
+		}
+		if strings.Join(result.Checkpoint.TraceIDs, ",") != "trace-0,trace-1,trace-2" {
+			t.Errorf("expected the skipped and exported traces to be remembered, got %v", result.Checkpoint.TraceIDs)
+		}
+	})
+	t.Run("should request traces from the overlap before the checkpoint", func(t *testing.T) {
+		var from string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			from = req.URL.Query().Get("fromTimestamp")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": []interface{}{}, "meta": map[string]interface{}{"page": 1, "totalPages": 1}})
+		}))
+		defer server.Close()
+		sdk := langfuse.New(context.TODO(), langfuse.Options{Host: server.URL, HttpClient: server.Client()})
+
+		checkpoint := &export.Checkpoint{Timestamp: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), TraceIDs: []string{"trace-1"}}
+		result, err := export.New(sdk, export.NewJSONLWriter(&bytes.Buffer{}), export.Options{Checkpoint: checkpoint, Overlap: time.Hour}).Export(context.TODO())
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if from != "2024-01-01T11:00:00Z" {
+			t.Errorf("expected the list to start an hour before the checkpoint, got %s", from)
+		}
+		if !result.Checkpoint.Timestamp.Equal(checkpoint.Timestamp) {
+			t.Errorf("expected the checkpoint to be kept when nothing is exported, got %s", result.Checkpoint.Timestamp)
+		}
+	})
+}
+
+func TestParquetWriter(t *testing.T) {
+	t.Run("should write a file with the parquet header and footer", func(t *testing.T) {
+		var buf bytes.Buffer
+		writer, err := export.NewWriter("parquet", &buf)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		for i := 0; i < 3; i++ {
+			if err = writer.Write(&export.Row{RecordType: export.RecordTypeTrace, TraceID: fmt.Sprintf("trace-%d", i)}); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+		}
+		if err = writer.Close(); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		data := buf.Bytes()
+		if string(data[:4]) != "PAR1" || string(data[len(data)-4:]) != "PAR1" {
+			t.Fatalf("expected the file to start and end with the parquet magic bytes")
+		}
+		footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+		footer := data[len(data)-8-footerLength : len(data)-8]
+		for _, column := range export.Columns {
+			if !bytes.Contains(footer, []byte(column)) {
+				t.Errorf("expected the footer schema to include column %s", column)
+			}
+		}
+		if !bytes.Contains(data[4:len(data)-8-footerLength], []byte("trace-2")) {
+			t.Errorf("expected the row values to be written before the footer")
+		}
+	})
+	t.Run("should write a valid file without rows", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := export.NewParquetWriter(&buf).Close(); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if !bytes.HasPrefix(buf.Bytes(), []byte("PAR1")) || !bytes.HasSuffix(buf.Bytes(), []byte("PAR1")) {
+			t.Errorf("expected an empty parquet file")
+		}
+	})
+}
+
+func TestCheckpoint(t *testing.T) {
+	t.Run("should return the zero time if there is no checkpoint", func(t *testing.T) {
+		checkpoint, err := export.LoadCheckpoint(filepath.Join(t.TempDir(), "missing"))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if !checkpoint.Timestamp.IsZero() {
+			t.Errorf("expected zero time, got %s", checkpoint.Timestamp)
+		}
+	})
+	t.Run("should load a saved checkpoint", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "checkpoint")
+		expected := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+		if err := export.SaveCheckpoint(path, &export.Checkpoint{Timestamp: expected, TraceIDs: []string{"trace-1"}}); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		checkpoint, err := export.LoadCheckpoint(path)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if !checkpoint.Timestamp.Equal(expected) {
+			t.Errorf("expected %s, got %s", expected, checkpoint.Timestamp)
+		}
+		if len(checkpoint.TraceIDs) != 1 || checkpoint.TraceIDs[0] != "trace-1" {
+			t.Errorf("expected the trace ids to be loaded, got %v", checkpoint.TraceIDs)
+		}
+	})
+	t.Run("should load a checkpoint saved as a timestamp", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "checkpoint")
+		if err := os.WriteFile(path, []byte("2024-01-02T03:04:05Z\n"), 0644); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		checkpoint, err := export.LoadCheckpoint(path)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if !checkpoint.Timestamp.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+			t.Errorf("expected the timestamp to be loaded, got %s", checkpoint.Timestamp)
+		}
+	})
+}
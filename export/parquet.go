@@ -0,0 +1,311 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// DefaultRowGroupSize is the number of rows buffered before a parquet row group is written
+const DefaultRowGroupSize = 10000
+
+const parquetMagic = "PAR1"
+
+// parquet enums, see https://github.com/apache/parquet-format/blob/master/src/main/thrift/parquet.thrift
+const (
+	parquetTypeByteArray    = 6
+	parquetRepetitionOption = 1
+	parquetConvertedUTF8    = 0
+	parquetEncodingPlain    = 0
+	parquetEncodingRLE      = 3
+	parquetCodecNone        = 0
+	parquetPageData         = 0
+)
+
+// ParquetWriter writes rows as a parquet file with one optional string column per entry in Columns. Values are
+// stored uncompressed with plain encoding and empty values are written as nulls. Rows are buffered in memory until
+// RowGroupSize rows have been written so memory use is bounded by the row group size rather than the export size
+type ParquetWriter struct {
+	RowGroupSize int
+	writer       *countingWriter
+	rows         [][]string
+	rowGroups    []parquetRowGroup
+	numRows      int64
+	err          error
+}
+
+type parquetColumnChunk struct {
+	offset int64
+	size   int64
+	values int64
+}
+
+type parquetRowGroup struct {
+	columns []parquetColumnChunk
+	size    int64
+	rows    int64
+}
+
+type countingWriter struct {
+	w     io.Writer
+	count int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// NewParquetWriter writes rows as parquet. The file footer is written on Close
+func NewParquetWriter(w io.Writer) *ParquetWriter {
+	return &ParquetWriter{
+		RowGroupSize: DefaultRowGroupSize,
+		writer:       &countingWriter{w: w},
+	}
+}
+
+func (p *ParquetWriter) Write(row *Row) error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.writer.count == 0 {
+		if _, p.err = io.WriteString(p.writer, parquetMagic); p.err != nil {
+			return p.err
+		}
+	}
+	p.rows = append(p.rows, row.Values())
+	if len(p.rows) >= p.RowGroupSize {
+		p.err = p.flushRowGroup()
+	}
+	return p.err
+}
+
+// Close writes the buffered rows and the file footer. A writer with no rows still produces a valid empty file
+func (p *ParquetWriter) Close() error {
+	if p.err != nil {
+		return p.err
+	}
+	if p.writer.count == 0 {
+		if _, p.err = io.WriteString(p.writer, parquetMagic); p.err != nil {
+			return p.err
+		}
+	}
+	if len(p.rows) > 0 {
+		if p.err = p.flushRowGroup(); p.err != nil {
+			return p.err
+		}
+	}
+	footer := p.footer()
+	if _, p.err = p.writer.Write(footer); p.err != nil {
+		return p.err
+	}
+	length := make([]byte, 4)
+	binary.LittleEndian.PutUint32(length, uint32(len(footer)))
+	if _, p.err = p.writer.Write(length); p.err != nil {
+		return p.err
+	}
+	_, p.err = io.WriteString(p.writer, parquetMagic)
+	return p.err
+}
+
+// flushRowGroup writes each column of the buffered rows as a single data page
+func (p *ParquetWriter) flushRowGroup() error {
+	group := parquetRowGroup{rows: int64(len(p.rows))}
+	for column := range Columns {
+		var levels []bool
+		var values bytes.Buffer
+		for _, row := range p.rows {
+			value := row[column]
+			levels = append(levels, value != "")
+			if value != "" {
+				_ = binary.Write(&values, binary.LittleEndian, uint32(len(value)))
+				values.WriteString(value)
+			}
+		}
+		var page bytes.Buffer
+		definitionLevels := encodeLevels(levels)
+		_ = binary.Write(&page, binary.LittleEndian, uint32(len(definitionLevels)))
+		page.Write(definitionLevels)
+		page.Write(values.Bytes())
+
+		header := &thriftWriter{}
+		header.i32(1, parquetPageData)
+		header.i32(2, int32(page.Len()))
+		header.i32(3, int32(page.Len()))
+		header.begin(5)
+		header.i32(1, int32(len(p.rows)))
+		header.i32(2, parquetEncodingPlain)
+		header.i32(3, parquetEncodingRLE)
+		header.i32(4, parquetEncodingRLE)
+		header.end()
+		header.end()
+
+		chunk := parquetColumnChunk{offset: p.writer.count, values: int64(len(p.rows))}
+		if _, err := p.writer.Write(header.buf.Bytes()); err != nil {
+			return err
+		}
+		if _, err := p.writer.Write(page.Bytes()); err != nil {
+			return err
+		}
+		chunk.size = p.writer.count - chunk.offset
+		group.size += chunk.size
+		group.columns = append(group.columns, chunk)
+	}
+	p.rowGroups = append(p.rowGroups, group)
+	p.numRows += group.rows
+	p.rows = p.rows[:0]
+	return nil
+}
+
+// footer encodes the FileMetaData struct
+func (p *ParquetWriter) footer() []byte {
+	t := &thriftWriter{}
+	t.i32(1, 1)
+	t.list(2, thriftStruct, len(Columns)+1)
+	t.beginElement()
+	t.string(4, "schema")
+	t.i32(5, int32(len(Columns)))
+	t.end()
+	for _, column := range Columns {
+		t.beginElement()
+		t.i32(1, parquetTypeByteArray)
+		t.i32(3, parquetRepetitionOption)
+		t.string(4, column)
+		t.i32(6, parquetConvertedUTF8)
+		t.end()
+	}
+	t.i64(3, p.numRows)
+	t.list(4, thriftStruct, len(p.rowGroups))
+	for _, group := range p.rowGroups {
+		t.beginElement()
+		t.list(1, thriftStruct, len(group.columns))
+		for i, chunk := range group.columns {
+			t.beginElement()
+			t.i64(2, chunk.offset)
+			t.begin(3)
+			t.i32(1, parquetTypeByteArray)
+			t.list(2, thriftI32, 2)
+			t.varint(parquetEncodingPlain)
+			t.varint(parquetEncodingRLE)
+			t.list(3, thriftBinary, 1)
+			t.bytes(Columns[i])
+			t.i32(4, parquetCodecNone)
+			t.i64(5, chunk.values)
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset)
+			t.end()
+			t.end()
+		}
+		t.i64(2, group.size)
+		t.i64(3, group.rows)
+		t.end()
+	}
+	t.string(6, "langfuse-go")
+	t.end()
+	return t.buf.Bytes()
+}
+
+// encodeLevels encodes definition levels with a bit width of 1 as runs of the rle/bit-packing hybrid encoding
+func encodeLevels(levels []bool) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+		writeUvarint(&buf, uint64(j-i)<<1)
+		if levels[i] {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+		i = j
+	}
+	return buf.Bytes()
+}
+
+func writeUvarint(buf *bytes.Buffer, value uint64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	buf.Write(b[:binary.PutUvarint(b, value)])
+}
+
+// thrift compact protocol types
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter is the subset of the thrift compact protocol needed for the parquet metadata
+type thriftWriter struct {
+	buf    bytes.Buffer
+	last   int16
+	parent []int16
+}
+
+func (t *thriftWriter) field(id int16, fieldType byte) {
+	if delta := id - t.last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		t.buf.WriteByte(fieldType)
+		t.varint(int64(id))
+	}
+	t.last = id
+}
+
+func (t *thriftWriter) varint(value int64) {
+	writeUvarint(&t.buf, uint64((value<<1)^(value>>63)))
+}
+
+func (t *thriftWriter) bytes(value string) {
+	writeUvarint(&t.buf, uint64(len(value)))
+	t.buf.WriteString(value)
+}
+
+func (t *thriftWriter) i32(id int16, value int32) {
+	t.field(id, thriftI32)
+	t.varint(int64(value))
+}
+
+func (t *thriftWriter) i64(id int16, value int64) {
+	t.field(id, thriftI64)
+	t.varint(value)
+}
+
+func (t *thriftWriter) string(id int16, value string) {
+	t.field(id, thriftBinary)
+	t.bytes(value)
+}
+
+func (t *thriftWriter) list(id int16, elementType byte, size int) {
+	t.field(id, thriftList)
+	if size < 15 {
+		t.buf.WriteByte(byte(size)<<4 | elementType)
+		return
+	}
+	t.buf.WriteByte(0xf0 | elementType)
+	writeUvarint(&t.buf, uint64(size))
+}
+
+// begin starts a struct field, beginElement a struct inside a list. Both are closed with end
+func (t *thriftWriter) begin(id int16) {
+	t.field(id, thriftStruct)
+	t.beginElement()
+}
+
+func (t *thriftWriter) beginElement() {
+	t.parent = append(t.parent, t.last)
+	t.last = 0
+}
+
+func (t *thriftWriter) end() {
+	t.buf.WriteByte(0)
+	if len(t.parent) > 0 {
+		t.last = t.parent[len(t.parent)-1]
+		t.parent = t.parent[:len(t.parent)-1]
+	}
+}
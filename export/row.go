@@ -0,0 +1,169 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/wepala/langfuse-go/api"
+)
+
+const (
+	RecordTypeTrace       = "trace"
+	RecordTypeObservation = "observation"
+	RecordTypeScore       = "score"
+)
+
+// Row is a flattened record of a trace, observation or score. Trace columns are repeated on observation and score
+// rows so each row can be analysed without joins
+type Row struct {
+	RecordType     string     `json:"record_type"`
+	TraceID        string     `json:"trace_id"`
+	TraceName      string     `json:"trace_name,omitempty"`
+	TraceTimestamp time.Time  `json:"trace_timestamp"`
+	UserID         string     `json:"user_id,omitempty"`
+	SessionID      string     `json:"session_id,omitempty"`
+	Release        string     `json:"release,omitempty"`
+	Version        string     `json:"version,omitempty"`
+	Tags           []string   `json:"tags,omitempty"`
+	ObservationID  string     `json:"observation_id,omitempty"`
+	Type           string     `json:"observation_type,omitempty"`
+	Name           string     `json:"name,omitempty"`
+	ParentID       string     `json:"parent_observation_id,omitempty"`
+	StartTime      *time.Time `json:"start_time,omitempty"`
+	EndTime        *time.Time `json:"end_time,omitempty"`
+	LatencyMs      *int64     `json:"latency_ms,omitempty"`
+	Model          string     `json:"model,omitempty"`
+	Level          string     `json:"level,omitempty"`
+	StatusMessage  string     `json:"status_message,omitempty"`
+	InputTokens    *int       `json:"input_tokens,omitempty"`
+	OutputTokens   *int       `json:"output_tokens,omitempty"`
+	TotalTokens    *int       `json:"total_tokens,omitempty"`
+	ScoreID        string     `json:"score_id,omitempty"`
+	ScoreValue     *float64   `json:"score_value,omitempty"`
+	Comment        string     `json:"comment,omitempty"`
+	Input          string     `json:"input,omitempty"`
+	Output         string     `json:"output,omitempty"`
+	Metadata       string     `json:"metadata,omitempty"`
+}
+
+// Columns are the column names used by the columnar writers, in order
+var Columns = []string{
+	"record_type", "trace_id", "trace_name", "trace_timestamp", "user_id", "session_id", "release", "version", "tags",
+	"observation_id", "observation_type", "name", "parent_observation_id", "start_time", "end_time", "latency_ms",
+	"model", "level", "status_message", "input_tokens", "output_tokens", "total_tokens", "score_id", "score_value",
+	"comment", "input", "output", "metadata",
+}
+
+// Rows flattens a trace with its observations and scores into one row per trace, observation and score
+func Rows(trace *api.TraceWithFullDetails) []*Row {
+	base := Row{
+		TraceID:        trace.Id,
+		TraceName:      stringValue(trace.Name),
+		TraceTimestamp: trace.Timestamp,
+		UserID:         stringValue(trace.UserId),
+		SessionID:      stringValue(trace.SessionId),
+		Release:        stringValue(trace.Release),
+		Version:        stringValue(trace.Version),
+		Tags:           trace.Tags,
+	}
+
+	rows := make([]*Row, 0, 1+len(trace.Observations)+len(trace.Scores))
+	traceRow := base
+	traceRow.RecordType = RecordTypeTrace
+	traceRow.Name = traceRow.TraceName
+	traceRow.Input = jsonValue(trace.Input)
+	traceRow.Output = jsonValue(trace.Output)
+	traceRow.Metadata = jsonValue(trace.Metadata)
+	rows = append(rows, &traceRow)
+
+	for _, observation := range trace.Observations {
+		row := base
+		row.RecordType = RecordTypeObservation
+		row.ObservationID = observation.Id
+		row.Type = observation.Type
+		row.Name = stringValue(observation.Name)
+		row.ParentID = stringValue(observation.ParentObservationId)
+		startTime := observation.StartTime
+		row.StartTime = &startTime
+		row.EndTime = observation.EndTime
+		if observation.EndTime != nil {
+			latency := observation.EndTime.Sub(observation.StartTime).Milliseconds()
+			row.LatencyMs = &latency
+		}
+		row.Model = stringValue(observation.Model)
+		row.Level = string(observation.Level)
+		row.StatusMessage = stringValue(observation.StatusMessage)
+		if observation.Usage != nil {
+			row.InputTokens = observation.Usage.Input
+			row.OutputTokens = observation.Usage.Output
+			row.TotalTokens = observation.Usage.Total
+		}
+		row.Input = jsonValue(observation.Input)
+		row.Output = jsonValue(observation.Output)
+		row.Metadata = jsonValue(observation.Metadata)
+		rows = append(rows, &row)
+	}
+
+	for _, score := range trace.Scores {
+		row := base
+		row.RecordType = RecordTypeScore
+		row.ScoreID = score.Id
+		row.Name = score.Name
+		row.ObservationID = stringValue(score.ObservationId)
+		value := score.Value
+		row.ScoreValue = &value
+		row.Comment = stringValue(score.Comment)
+		rows = append(rows, &row)
+	}
+
+	return rows
+}
+
+// Values returns the row as strings in the same order as Columns
+func (r *Row) Values() []string {
+	return []string{
+		r.RecordType, r.TraceID, r.TraceName, formatTime(&r.TraceTimestamp), r.UserID, r.SessionID, r.Release,
+		r.Version, strings.Join(r.Tags, ","), r.ObservationID, r.Type, r.Name, r.ParentID, formatTime(r.StartTime),
+		formatTime(r.EndTime), formatNumber(r.LatencyMs), r.Model, r.Level, r.StatusMessage, formatNumber(r.InputTokens),
+		formatNumber(r.OutputTokens), formatNumber(r.TotalTokens), r.ScoreID, formatNumber(r.ScoreValue), r.Comment,
+		r.Input, r.Output, r.Metadata,
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// jsonValue encodes free form fields as json so they fit in a single column
+func jsonValue(value *interface{}) string {
+	if value == nil || *value == nil {
+		return ""
+	}
+	if s, ok := (*value).(string); ok {
+		return s
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
+
+func formatTime(value *time.Time) string {
+	if value == nil || value.IsZero() {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339Nano)
+}
+
+func formatNumber[T int | int64 | float64](value *T) string {
+	if value == nil {
+		return ""
+	}
+	encoded, _ := json.Marshal(*value)
+	return string(encoded)
+}
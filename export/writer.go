@@ -0,0 +1,70 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Writer writes flattened rows to an output format
+type Writer interface {
+	Write(row *Row) error
+	// Close flushes any buffered rows. It does not close the underlying io.Writer
+	Close() error
+}
+
+// NewWriter returns a writer for the named format. Supported formats are jsonl, csv and parquet
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case "jsonl", "":
+		return NewJSONLWriter(w), nil
+	case "csv":
+		return NewCSVWriter(w), nil
+	case "parquet":
+		return NewParquetWriter(w), nil
+	}
+	return nil, fmt.Errorf("unsupported export format %s", format)
+}
+
+type JSONLWriter struct {
+	encoder *json.Encoder
+}
+
+// NewJSONLWriter writes one json object per line
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	return &JSONLWriter{encoder: json.NewEncoder(w)}
+}
+
+func (j *JSONLWriter) Write(row *Row) error {
+	return j.encoder.Encode(row)
+}
+
+func (j *JSONLWriter) Close() error {
+	return nil
+}
+
+type CSVWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+// NewCSVWriter writes rows as csv with a header row containing Columns
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{writer: csv.NewWriter(w)}
+}
+
+func (c *CSVWriter) Write(row *Row) error {
+	if !c.headerWritten {
+		if err := c.writer.Write(Columns); err != nil {
+			return err
+		}
+		c.headerWritten = true
+	}
+	return c.writer.Write(row.Values())
+}
+
+func (c *CSVWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
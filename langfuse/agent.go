@@ -0,0 +1,119 @@
+package langfuse
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Metadata keys used to describe tool and agent spans
+const METADATA_TYPE = "type"
+const METADATA_TOOL_CALL_ID = "toolCallId"
+const METADATA_GENERATION_ID = "generationId"
+const METADATA_ITERATIONS = "iterations"
+const METADATA_ITERATION = "iteration"
+
+const OBSERVATION_TOOL = "tool"
+const OBSERVATION_AGENT = "agent"
+
+// Tool is a span for a tool call. The name is the name of the tool and the arguments are its input
+type Tool struct {
+	Span
+	Arguments interface{}
+	// ToolCallID is the id the model gave the tool call
+	ToolCallID string
+	// GenerationID is the generation that requested the tool call
+	GenerationID string
+}
+
+// ToolFromCall creates the options for a tool span from a tool call made by the generation
+func ToolFromCall(call ToolCall, generationID string) *Tool {
+	var arguments interface{} = call.Arguments
+	if json.Valid([]byte(call.Arguments)) {
+		arguments = json.RawMessage(call.Arguments)
+	}
+	return &Tool{
+		Span:         Span{BasicObservation: BasicObservation{Name: call.Name}},
+		Arguments:    arguments,
+		ToolCallID:   call.ID,
+		GenerationID: generationID,
+	}
+}
+
+func (o *BasicObservation) Tool(opts *Tool) (*Tool, error) {
+	if opts == nil {
+		opts = &Tool{}
+	}
+
+	if opts.Arguments != nil && opts.Input == nil {
+		opts.Input = opts.Arguments
+	}
+	opts.Metadata = withMetadata(opts.Metadata, map[string]interface{}{
+		METADATA_TYPE:          OBSERVATION_TOOL,
+		METADATA_TOOL_CALL_ID:  opts.ToolCallID,
+		METADATA_GENERATION_ID: opts.GenerationID,
+	})
+
+	_, err := o.Span(&opts.Span)
+	return opts, err
+}
+
+// Agent is a span for an agent loop. Each pass through the loop is an iteration
+type Agent struct {
+	Span
+	iterations int
+}
+
+func (o *BasicObservation) Agent(opts *Agent) (*Agent, error) {
+	if opts == nil {
+		opts = &Agent{}
+	}
+
+	opts.Metadata = withMetadata(opts.Metadata, map[string]interface{}{
+		METADATA_TYPE: OBSERVATION_AGENT,
+	})
+
+	_, err := o.Span(&opts.Span)
+	return opts, err
+}
+
+// Iteration starts the next iteration of the loop, recording it as an event, and returns the iteration number
+func (a *Agent) Iteration() (int, error) {
+	l := a.locker()
+	l.Lock()
+	a.iterations++
+	iteration := a.iterations
+	l.Unlock()
+	a.SetMetadata(METADATA_ITERATIONS, iteration)
+
+	_, err := a.Event(&Event{
+		BasicObservation: BasicObservation{
+			Name:     fmt.Sprintf("iteration %d", iteration),
+			Metadata: map[string]interface{}{METADATA_ITERATION: iteration},
+		},
+		StartTime: time.Now(),
+	})
+	return iteration, err
+}
+
+// Iterations is the number of iterations that have been started
+func (a *Agent) Iterations() int {
+	l := a.locker()
+	l.RLock()
+	defer l.RUnlock()
+	return a.iterations
+}
+
+// withMetadata returns a copy of the metadata with the values that aren't empty added
+func withMetadata(metadata map[string]interface{}, values map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(metadata)+len(values))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	for key, value := range values {
+		if value != "" {
+			copied[key] = value
+		}
+	}
+	return copied
+}
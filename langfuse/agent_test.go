@@ -0,0 +1,66 @@
+package langfuse_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestBasicObservation_Agent(t *testing.T) {
+	t.Run("should record tool calls and iterations under the agent", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
+		agent, err := trace.Agent(&langfuse.Agent{Span: langfuse.Span{BasicObservation: langfuse.BasicObservation{Name: "researcher"}}})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		iteration, err := agent.Iteration()
+		if err != nil || iteration != 1 {
+			t.Fatalf("expected iteration %d, got %d %v", 1, iteration, err)
+		}
+		generation, _ := agent.Generation(&langfuse.Generation{Model: "gpt-4"})
+		call := langfuse.ToolCall{ID: "call-1", Name: "search", Arguments: `{"query":"langfuse"}`}
+		_ = generation.EndWithOutput(langfuse.AssistantMessage("", call))
+		tool, err := agent.Tool(langfuse.ToolFromCall(call, generation.ID))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		_ = tool.EndWithError(errors.New("rate limited"))
+		_, _ = agent.Iteration()
+		_ = agent.End()
+		events := flush()
+		types := []string{langfuse.TRACE_CREATE, langfuse.SPAN_CREATE, langfuse.EVENT_CREATE, langfuse.GENERATION_CREATE, langfuse.GENERATION_UPDATE, langfuse.SPAN_CREATE, langfuse.SPAN_UPDATE, langfuse.EVENT_CREATE, langfuse.SPAN_UPDATE}
+		if len(events) != len(types) {
+			t.Fatalf("expected %d events to be sent, got %d", len(types), len(events))
+		}
+		for i, eventType := range types {
+			if events[i]["type"] != eventType {
+				t.Errorf("expected event %d to be %s, got %v", i, eventType, events[i]["type"])
+			}
+		}
+		toolBody := events[5]["body"].(map[string]interface{})
+		metadata := toolBody["metadata"].(map[string]interface{})
+		if toolBody["name"] != "search" || toolBody["parentObservationId"] != agent.ID {
+			t.Errorf("expected tool span under the agent, got %v", toolBody)
+		}
+		if metadata[langfuse.METADATA_TYPE] != langfuse.OBSERVATION_TOOL || metadata[langfuse.METADATA_TOOL_CALL_ID] != "call-1" || metadata[langfuse.METADATA_GENERATION_ID] != generation.ID {
+			t.Errorf("expected tool metadata, got %v", metadata)
+		}
+		if toolBody["input"].(map[string]interface{})["query"] != "langfuse" {
+			t.Errorf("expected arguments to be the input, got %v", toolBody["input"])
+		}
+		if events[6]["body"].(map[string]interface{})["level"] != langfuse.LEVEL_ERROR {
+			t.Errorf("expected the tool error to be recorded")
+		}
+		iterationBody := events[7]["body"].(map[string]interface{})
+		if iterationBody["name"] != "iteration 2" || iterationBody["parentObservationId"] != agent.ID {
+			t.Errorf("expected iteration event under the agent, got %v", iterationBody)
+		}
+		agentMetadata := events[8]["body"].(map[string]interface{})["metadata"].(map[string]interface{})
+		if agentMetadata[langfuse.METADATA_TYPE] != langfuse.OBSERVATION_AGENT || agentMetadata[langfuse.METADATA_ITERATIONS] != float64(2) {
+			t.Errorf("expected agent metadata, got %v", agentMetadata)
+		}
+	})
+}
@@ -2,7 +2,6 @@ package langfuse
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"sync"
@@ -15,14 +14,14 @@ import (
 
 type Queue struct {
 	id        int
-	Events    []interface{}
+	Events    []api.IngestionEvent
 	nextEntry int
 	mu        sync.Mutex
 	maxItems  int
 }
 
 func (q *Queue) Reset() {
-	q.Events = make([]interface{}, q.maxItems)
+	q.Events = make([]api.IngestionEvent, q.maxItems)
 	q.nextEntry = 0
 }
 
@@ -37,7 +36,7 @@ func NewBatchEventManager(client *client.Client, totalQueues int, maxBatchItems
 	}
 
 	for i := 0; i < totalQueues; i++ {
-		queues = append(queues, &Queue{id: i, Events: make([]interface{}, maxBatchItems), maxItems: maxBatchItems})
+		queues = append(queues, &Queue{id: i, Events: make([]api.IngestionEvent, maxBatchItems), maxItems: maxBatchItems})
 	}
 	return &BatchEventManager{
 		Client:        client,
@@ -57,6 +56,12 @@ func (b *BatchEventManager) Enqueue(id string, eventType string, event interface
 		id = ksuid.New().String()
 	}
 
+	//build the event before looking for a queue so the queue lock isn't held while the event is converted
+	ingestionEvent, err := b.newEvent(id, eventType, event)
+	if err != nil {
+		return err
+	}
+
 	//find the next available queue
 	var queue *Queue
 	for _, queue = range b.Queues {
@@ -65,19 +70,7 @@ func (b *BatchEventManager) Enqueue(id string, eventType string, event interface
 			queue.mu.Unlock()
 			continue
 		}
-		//convert to a simple map since using the observation objects isn't safe for concurrent use
-		bodyBytes, err := json.Marshal(event)
-		if err != nil {
-			return err
-		}
-		var body map[string]interface{}
-		err = json.Unmarshal(bodyBytes, &body)
-		queue.Events[queue.nextEntry] = map[string]interface{}{
-			"id":        id,
-			"type":      eventType,
-			"body":      body,
-			"timestamp": time.Now().UTC().Format(time.RFC3339),
-		}
+		queue.Events[queue.nextEntry] = *ingestionEvent
 		queue.nextEntry++
 		log.Printf("add to queue %d,queue length %d, max %d", queue.id, queue.nextEntry, b.maxBatchItems)
 		queue.mu.Unlock()
@@ -87,6 +80,19 @@ func (b *BatchEventManager) Enqueue(id string, eventType string, event interface
 	return fmt.Errorf("no queue available")
 }
 
+// newEvent converts the sdk's observations to typed ingestion events. Values that aren't sdk observations or
+// ingestion events can't be sent and are rejected
+func (b *BatchEventManager) newEvent(id string, eventType string, event interface{}) (*api.IngestionEvent, error) {
+	ingestionEvent, ok, err := newIngestionEvent(id, eventType, event, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("unsupported event %T for %s", event, eventType)
+	}
+	return ingestionEvent, nil
+}
+
 func (b *BatchEventManager) Process(ctxt context.Context) {
 	for {
 		select {
@@ -105,7 +111,10 @@ func (b *BatchEventManager) Flush(ctxt context.Context) {
 	var wg sync.WaitGroup
 	var queue *Queue
 	for _, queue = range b.Queues {
-		if queue.nextEntry == 0 {
+		queue.mu.Lock()
+		empty := queue.nextEntry == 0
+		queue.mu.Unlock()
+		if empty {
 			continue
 		}
 		wg.Add(1)
@@ -116,7 +125,11 @@ func (b *BatchEventManager) Flush(ctxt context.Context) {
 			if q.nextEntry == 0 {
 				return
 			}
-			resp, _ := b.Client.Ingestion.Batch(ctxt, &api.IngestionBatchRequest{Batch: q.Events[:q.nextEntry]})
+			batch := make([]*api.IngestionEvent, q.nextEntry)
+			for i := range batch {
+				batch[i] = &q.Events[i]
+			}
+			resp, _ := b.Client.Ingestion.Batch(ctxt, &api.IngestionBatchRequest{Batch: batch})
 
 			if resp != nil && len(resp.Errors) > 0 {
 				log.Printf("error sending batch: %v", &resp.Errors)
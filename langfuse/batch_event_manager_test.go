@@ -20,7 +20,7 @@ func TestBatchEventManager_Enqueue(t *testing.T) {
 		if eventManager == nil {
 			t.Fatal("expected event manager to be created")
 		}
-		err := eventManager.Enqueue("test", "test", map[string]interface{}{})
+		err := eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 		if err != nil {
 			t.Fatalf("expected enqueue to succeed, got %s", err.Error())
 		}
@@ -35,11 +35,11 @@ func TestBatchEventManager_Enqueue(t *testing.T) {
 		if eventManager == nil {
 			t.Fatal("expected event manager to be created")
 		}
-		err := eventManager.Enqueue("test", "test", map[string]interface{}{})
+		err := eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 		if err != nil {
 			t.Fatalf("expected enqueue to succeed, got %s", err.Error())
 		}
-		err = eventManager.Enqueue("test", "test", map[string]interface{}{})
+		err = eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 		if err == nil {
 			t.Fatalf("expected enqueue to fail")
 		}
@@ -57,7 +57,7 @@ func TestBatchEventManager_Enqueue(t *testing.T) {
 		wg.Add(3)
 		go func() {
 			defer wg.Done()
-			err := eventManager.Enqueue("test", "test", map[string]interface{}{})
+			err := eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 			if err != nil {
 				t.Errorf("expected enqueue to succeed, got %s", err.Error())
 				return
@@ -65,20 +65,20 @@ func TestBatchEventManager_Enqueue(t *testing.T) {
 		}()
 		go func() {
 			defer wg.Done()
-			err := eventManager.Enqueue("test", "test", map[string]interface{}{})
+			err := eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 			if err != nil {
 				t.Errorf("expected enqueue to succeed, got %s", err.Error())
 			}
 		}()
 		go func() {
 			defer wg.Done()
-			err := eventManager.Enqueue("test", "test", map[string]interface{}{})
+			err := eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 			if err != nil {
 				t.Errorf("expected enqueue to succeed, got %s", err.Error())
 			}
 		}()
 		wg.Wait()
-		if eventManager.Queues[1].Events[1] != nil {
+		if eventManager.Queues[1].Events[1].Type != "" {
 			t.Errorf("expected %d event to be enqueued, got an even in the other available slot", 1)
 		}
 	})
@@ -100,15 +100,15 @@ func TestBatchEventManager_Enqueue(t *testing.T) {
 		if len(eventManager.Queues) != 3 {
 			t.Fatalf("expected %d queue to be created, got %d", 3, len(eventManager.Queues))
 		}
-		err := eventManager.Enqueue("test", "test", map[string]interface{}{})
+		err := eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 		if err != nil {
 			t.Fatalf("expected enqueue to succeed, got %s", err.Error())
 		}
-		err = eventManager.Enqueue("test", "test", map[string]interface{}{})
+		err = eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 		if err != nil {
 			t.Fatalf("expected enqueue to succeed, got %s", err.Error())
 		}
-		err = eventManager.Enqueue("test", "test", map[string]interface{}{})
+		err = eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 		if err != nil {
 			t.Fatalf("expected enqueue to succeed, got %s", err.Error())
 		}
@@ -116,7 +116,7 @@ func TestBatchEventManager_Enqueue(t *testing.T) {
 		success := make(chan bool)
 		go eventManager.Process(ctx)
 		go func() {
-			err = eventManager.Enqueue("test", "test", map[string]interface{}{})
+			err = eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 			if err != nil {
 				t.Errorf("expected enqueue to succeed, got %s", err.Error())
 			}
@@ -146,11 +146,11 @@ func TestBatchEventManager_Process(t *testing.T) {
 		if eventManager == nil {
 			t.Fatal("expected event manager to be created")
 		}
-		err := eventManager.Enqueue("test", "test", map[string]interface{}{})
+		err := eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 		if err != nil {
 			t.Fatalf("expected enqueue to succeed, got %s", err.Error())
 		}
-		err = eventManager.Enqueue("test", "test", map[string]interface{}{})
+		err = eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 		if err != nil {
 			t.Fatalf("expected enqueue to succeed, got %s", err.Error())
 		}
@@ -177,15 +177,15 @@ func TestBatchEventManager_Process(t *testing.T) {
 		if eventManager == nil {
 			t.Fatal("expected event manager to be created")
 		}
-		err := eventManager.Enqueue("test", "test", map[string]interface{}{})
+		err := eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 		if err != nil {
 			t.Fatalf("expected enqueue to succeed, got %s", err.Error())
 		}
-		err = eventManager.Enqueue("test", "test", map[string]interface{}{})
+		err = eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 		if err != nil {
 			t.Fatalf("expected enqueue to succeed, got %s", err.Error())
 		}
-		err = eventManager.Enqueue("test", "test", map[string]interface{}{})
+		err = eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 		if err != nil {
 			t.Fatalf("expected enqueue to succeed, got %s", err.Error())
 		}
@@ -244,7 +244,7 @@ func TestBatchEventManager_Process(t *testing.T) {
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 		defer cancel()
-		err := eventManager.Enqueue("test", "test", map[string]interface{}{})
+		err := eventManager.Enqueue("test", langfuse.TRACE_CREATE, &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
 		if err != nil {
 			t.Fatalf("expected enqueue to succeed, got %s", err.Error())
 		}
@@ -0,0 +1,192 @@
+package langfuse
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+const ROLE_SYSTEM = "system"
+const ROLE_USER = "user"
+const ROLE_ASSISTANT = "assistant"
+const ROLE_TOOL = "tool"
+
+// ChatMessage is a message in a chat prompt or completion. It serializes to the openai format that langfuse renders.
+// Messages with only text are serialized with the text as the content
+type ChatMessage struct {
+	Role       string
+	Content    []ContentPart
+	Name       string
+	ToolCalls  []ToolCall
+	ToolCallID string
+}
+
+func SystemMessage(text string) ChatMessage {
+	return ChatMessage{Role: ROLE_SYSTEM, Content: []ContentPart{TextPart(text)}}
+}
+
+func UserMessage(parts ...ContentPart) ChatMessage {
+	return ChatMessage{Role: ROLE_USER, Content: parts}
+}
+
+func AssistantMessage(text string, toolCalls ...ToolCall) ChatMessage {
+	message := ChatMessage{Role: ROLE_ASSISTANT, ToolCalls: toolCalls}
+	if text != "" {
+		message.Content = []ContentPart{TextPart(text)}
+	}
+	return message
+}
+
+// ToolMessage is the result of a tool call
+func ToolMessage(toolCallID string, result string) ChatMessage {
+	return ChatMessage{Role: ROLE_TOOL, ToolCallID: toolCallID, Content: []ContentPart{TextPart(result)}}
+}
+
+// Text returns the text parts of the message
+func (m ChatMessage) Text() string {
+	var text string
+	for _, part := range m.Content {
+		if part.Type == CONTENT_TEXT {
+			text += part.Text
+		}
+	}
+	return text
+}
+
+type chatMessage struct {
+	Role       string          `json:"role"`
+	Content    json.RawMessage `json:"content,omitempty"`
+	Name       string          `json:"name,omitempty"`
+	ToolCalls  []ToolCall      `json:"tool_calls,omitempty"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+}
+
+func (m ChatMessage) MarshalJSON() ([]byte, error) {
+	message := chatMessage{
+		Role:       m.Role,
+		Name:       m.Name,
+		ToolCalls:  m.ToolCalls,
+		ToolCallID: m.ToolCallID,
+	}
+	var err error
+	switch {
+	case len(m.Content) == 1 && m.Content[0].Type == CONTENT_TEXT:
+		message.Content, err = json.Marshal(m.Content[0].Text)
+	case len(m.Content) > 0:
+		message.Content, err = json.Marshal(m.Content)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(message)
+}
+
+func (m *ChatMessage) UnmarshalJSON(data []byte) error {
+	var message chatMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return err
+	}
+	*m = ChatMessage{
+		Role:       message.Role,
+		Name:       message.Name,
+		ToolCalls:  message.ToolCalls,
+		ToolCallID: message.ToolCallID,
+	}
+	if len(message.Content) == 0 || string(message.Content) == "null" {
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(message.Content, &text); err == nil {
+		m.Content = []ContentPart{TextPart(text)}
+		return nil
+	}
+	if err := json.Unmarshal(message.Content, &m.Content); err != nil {
+		return errors.New("content must be a string or a list of content parts")
+	}
+	return nil
+}
+
+// ToolCall is a request from the model to call a tool. Arguments are the json encoded arguments
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments string
+}
+
+type toolCall struct {
+	ID       string `json:"id,omitempty"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+func (t ToolCall) MarshalJSON() ([]byte, error) {
+	call := toolCall{ID: t.ID, Type: "function"}
+	call.Function.Name = t.Name
+	call.Function.Arguments = t.Arguments
+	return json.Marshal(call)
+}
+
+func (t *ToolCall) UnmarshalJSON(data []byte) error {
+	var call toolCall
+	if err := json.Unmarshal(data, &call); err != nil {
+		return err
+	}
+	*t = ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: call.Function.Arguments}
+	return nil
+}
+
+// ToolDefinition describes a tool the model can call. Parameters is the json schema of the arguments
+type ToolDefinition struct {
+	Name        string
+	Description string
+	Parameters  interface{}
+}
+
+type toolDefinition struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string      `json:"name"`
+		Description string      `json:"description,omitempty"`
+		Parameters  interface{} `json:"parameters,omitempty"`
+	} `json:"function"`
+}
+
+func (t ToolDefinition) MarshalJSON() ([]byte, error) {
+	definition := toolDefinition{Type: "function"}
+	definition.Function.Name = t.Name
+	definition.Function.Description = t.Description
+	definition.Function.Parameters = t.Parameters
+	return json.Marshal(definition)
+}
+
+func (t *ToolDefinition) UnmarshalJSON(data []byte) error {
+	var definition toolDefinition
+	if err := json.Unmarshal(data, &definition); err != nil {
+		return err
+	}
+	*t = ToolDefinition{Name: definition.Function.Name, Description: definition.Function.Description, Parameters: definition.Function.Parameters}
+	return nil
+}
+
+// ChatInput is the input of a generation that was given tools. Generations without tools can use the messages as
+// the input
+type ChatInput struct {
+	Messages []ChatMessage    `json:"messages"`
+	Tools    []ToolDefinition `json:"tools,omitempty"`
+}
+
+// SetChatInput sets the messages, and the tools if there are any, as the input
+func (g *Generation) SetChatInput(messages []ChatMessage, tools ...ToolDefinition) {
+	if len(tools) == 0 {
+		g.SetInput(messages)
+		return
+	}
+	g.SetInput(&ChatInput{Messages: messages, Tools: tools})
+}
+
+// SetChatOutput sets the message the model responded with as the output
+func (g *Generation) SetChatOutput(message ChatMessage) {
+	g.SetOutput(message)
+}
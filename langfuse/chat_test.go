@@ -0,0 +1,69 @@
+package langfuse_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestChatMessage(t *testing.T) {
+	t.Run("should serialize to the openai format", func(t *testing.T) {
+		messages := []langfuse.ChatMessage{
+			langfuse.SystemMessage("you are helpful"),
+			langfuse.UserMessage(langfuse.TextPart("what is in this image?"), langfuse.ImageURLPart("https://example.com/cat.png")),
+			langfuse.AssistantMessage("", langfuse.ToolCall{ID: "call-1", Name: "classify", Arguments: `{"url":"https://example.com/cat.png"}`}),
+			langfuse.ToolMessage("call-1", "cat"),
+		}
+		data, err := json.Marshal(messages)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		expected := `[{"role":"system","content":"you are helpful"},` +
+			`{"role":"user","content":[{"type":"text","text":"what is in this image?"},{"type":"image_url","image_url":{"url":"https://example.com/cat.png"}}]},` +
+			`{"role":"assistant","tool_calls":[{"id":"call-1","type":"function","function":{"name":"classify","arguments":"{\"url\":\"https://example.com/cat.png\"}"}}]},` +
+			`{"role":"tool","content":"cat","tool_call_id":"call-1"}]`
+		if string(data) != expected {
+			t.Errorf("expected %s, got %s", expected, data)
+		}
+		var decoded []langfuse.ChatMessage
+		if err = json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if decoded[0].Text() != "you are helpful" || len(decoded[1].Content) != 2 || decoded[2].ToolCalls[0].Name != "classify" || decoded[3].ToolCallID != "call-1" {
+			t.Errorf("expected messages to be decoded, got %v", decoded)
+		}
+	})
+	t.Run("should serialize tool definitions", func(t *testing.T) {
+		data, err := json.Marshal(langfuse.ToolDefinition{Name: "classify", Description: "classifies an image", Parameters: map[string]interface{}{"type": "object"}})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		expected := `{"type":"function","function":{"name":"classify","description":"classifies an image","parameters":{"type":"object"}}}`
+		if string(data) != expected {
+			t.Errorf("expected %s, got %s", expected, data)
+		}
+	})
+}
+
+func TestGeneration_SetChatInput(t *testing.T) {
+	t.Run("should send the messages and tools", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		generation, _ := sdk.Generation(context.TODO(), &langfuse.Generation{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id"}})
+		generation.SetChatInput([]langfuse.ChatMessage{langfuse.UserMessage(langfuse.TextPart("hi"))}, langfuse.ToolDefinition{Name: "search"})
+		generation.SetChatOutput(langfuse.AssistantMessage("hello"))
+		_ = generation.End()
+		body := flush()[1]["body"].(map[string]interface{})
+		input := body["input"].(map[string]interface{})
+		if input["messages"].([]interface{})[0].(map[string]interface{})["content"] != "hi" {
+			t.Errorf("expected messages to be sent, got %v", input)
+		}
+		if input["tools"].([]interface{})[0].(map[string]interface{})["function"].(map[string]interface{})["name"] != "search" {
+			t.Errorf("expected tools to be sent, got %v", input)
+		}
+		if body["output"].(map[string]interface{})["content"] != "hello" {
+			t.Errorf("expected output to be sent, got %v", body["output"])
+		}
+	})
+}
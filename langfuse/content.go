@@ -0,0 +1,100 @@
+package langfuse
+
+import (
+	"encoding/base64"
+	"mime"
+	"strings"
+)
+
+const CONTENT_TEXT = "text"
+const CONTENT_IMAGE_URL = "image_url"
+const CONTENT_INPUT_AUDIO = "input_audio"
+const CONTENT_FILE = "file"
+
+// ContentPart is a part of a multimodal chat message. It serializes to the openai format that langfuse renders
+type ContentPart struct {
+	Type       string      `json:"type"`
+	Text       string      `json:"text,omitempty"`
+	ImageURL   *ImageURL   `json:"image_url,omitempty"`
+	InputAudio *InputAudio `json:"input_audio,omitempty"`
+	File       *File       `json:"file,omitempty"`
+}
+
+type ImageURL struct {
+	// URL is a link to the image or a data uri with the image
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type InputAudio struct {
+	// Data is the base64 encoded audio
+	Data   string `json:"data"`
+	Format string `json:"format"`
+}
+
+type File struct {
+	// FileData is a data uri with the file
+	FileData string `json:"file_data,omitempty"`
+	FileID   string `json:"file_id,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+func TextPart(text string) ContentPart {
+	return ContentPart{Type: CONTENT_TEXT, Text: text}
+}
+
+// ImageURLPart references an image by url
+func ImageURLPart(url string) ContentPart {
+	return ContentPart{Type: CONTENT_IMAGE_URL, ImageURL: &ImageURL{URL: url}}
+}
+
+// ImagePart includes the image in the message
+func ImagePart(data []byte, mimeType string) ContentPart {
+	return ImageURLPart(DataURI(data, mimeType))
+}
+
+// AudioPart includes audio in the message. The format is the audio encoding e.g. wav or mp3
+func AudioPart(data []byte, format string) ContentPart {
+	return ContentPart{Type: CONTENT_INPUT_AUDIO, InputAudio: &InputAudio{Data: base64.StdEncoding.EncodeToString(data), Format: format}}
+}
+
+// FilePart includes a file e.g. a pdf in the message
+func FilePart(data []byte, mimeType string, filename string) ContentPart {
+	return ContentPart{Type: CONTENT_FILE, File: &File{FileData: DataURI(data, mimeType), Filename: filename}}
+}
+
+// ContentPartFromDataURI creates the part that matches the type of the data uri. Strings that aren't data uris are
+// text parts
+func ContentPartFromDataURI(uri string) ContentPart {
+	mimeType, data, ok := ParseDataURI(uri)
+	switch {
+	case !ok:
+		return TextPart(uri)
+	case strings.HasPrefix(mimeType, "image/"):
+		return ImageURLPart(uri)
+	case strings.HasPrefix(mimeType, "audio/"):
+		return AudioPart(data, strings.TrimPrefix(strings.TrimPrefix(mimeType, "audio/"), "x-"))
+	}
+	return FilePart(data, mimeType, "")
+}
+
+// DataURI encodes the data as a base64 data uri
+func DataURI(data []byte, mimeType string) string {
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// ParseDataURI returns the mime type and the decoded data of a base64 data uri
+func ParseDataURI(uri string) (mimeType string, data []byte, ok bool) {
+	match := dataURI.FindStringSubmatch(uri)
+	if match == nil {
+		return "", nil, false
+	}
+	data, err := base64.StdEncoding.DecodeString(uri[len(match[0]):])
+	if err != nil {
+		return "", nil, false
+	}
+	if mediaType, _, err := mime.ParseMediaType(match[1]); err == nil {
+		return mediaType, data, true
+	}
+	return match[1], data, true
+}
@@ -0,0 +1,94 @@
+package langfuse_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestContentPart(t *testing.T) {
+	t.Run("should serialize to the openai format", func(t *testing.T) {
+		parts := []langfuse.ContentPart{
+			langfuse.TextPart("describe this"),
+			langfuse.ImagePart([]byte("png"), "image/png"),
+			langfuse.AudioPart([]byte("wav"), "wav"),
+		}
+		data, err := json.Marshal(parts)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		expected := `[{"type":"text","text":"describe this"},{"type":"image_url","image_url":{"url":"data:image/png;base64,cG5n"}},{"type":"input_audio","input_audio":{"data":"d2F2","format":"wav"}}]`
+		if string(data) != expected {
+			t.Errorf("expected %s, got %s", expected, data)
+		}
+	})
+	t.Run("should detect the type of data uris", func(t *testing.T) {
+		tests := []struct {
+			uri      string
+			expected string
+		}{
+			{"data:image/jpeg;base64,anBn", langfuse.CONTENT_IMAGE_URL},
+			{"data:audio/mp3;base64,bXAz", langfuse.CONTENT_INPUT_AUDIO},
+			{"data:application/pdf;base64,cGRm", langfuse.CONTENT_FILE},
+			{"just text", langfuse.CONTENT_TEXT},
+		}
+		for _, test := range tests {
+			if part := langfuse.ContentPartFromDataURI(test.uri); part.Type != test.expected {
+				t.Errorf("expected %s to be a %s part, got %s", test.uri, test.expected, part.Type)
+			}
+		}
+		if part := langfuse.ContentPartFromDataURI("data:audio/mp3;base64,bXAz"); part.InputAudio.Format != "mp3" || part.InputAudio.Data != "bXAz" {
+			t.Errorf("expected audio to be decoded, got %v", part.InputAudio)
+		}
+	})
+	t.Run("should remove media over the media limit", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		sdk = langfuse.New(context.TODO(), langfuse.Options{EventManager: sdk.EventManager(), Limits: &langfuse.LimitOptions{MaxMediaSize: 100}})
+		image := bytes.Repeat([]byte("x"), 200)
+		_, _ = sdk.Generation(context.TODO(), &langfuse.Generation{BasicObservation: langfuse.BasicObservation{
+			TraceID: "trace-id",
+			Input: []map[string]interface{}{{"role": "user", "content": []langfuse.ContentPart{
+				langfuse.TextPart("what is this?"),
+				langfuse.ImagePart(image, "image/png"),
+				langfuse.ImagePart([]byte("small"), "image/png"),
+				langfuse.AudioPart(image, "wav"),
+			}}},
+		}})
+		body := flush()[0]["body"].(map[string]interface{})
+		parts := body["input"].([]interface{})[0].(map[string]interface{})["content"].([]interface{})
+		if url := parts[1].(map[string]interface{})["image_url"].(map[string]interface{})["url"]; url != "[removed image/png 200 bytes]" {
+			t.Errorf("expected the large image to be removed, got %v", url)
+		}
+		if url := parts[2].(map[string]interface{})["image_url"].(map[string]interface{})["url"]; url != "data:image/png;base64,c21hbGw=" {
+			t.Errorf("expected the small image to be kept, got %v", url)
+		}
+		if data := parts[3].(map[string]interface{})["input_audio"].(map[string]interface{})["data"]; data != "[removed audio/wav 200 bytes]" {
+			t.Errorf("expected the audio to be removed, got %v", data)
+		}
+	})
+	t.Run("should only remove input audio parts that can be decoded", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		sdk = langfuse.New(context.TODO(), langfuse.Options{EventManager: sdk.EventManager(), Limits: &langfuse.LimitOptions{MaxMediaSize: 10}})
+		export := map[string]interface{}{"format": "csv", "data": "name,email\njane,jane.doe@example.com"}
+		invalid := map[string]interface{}{"type": langfuse.CONTENT_INPUT_AUDIO, "input_audio": map[string]interface{}{"format": "wav", "data": "not base64 at all!"}}
+		_, _ = sdk.Generation(context.TODO(), &langfuse.Generation{BasicObservation: langfuse.BasicObservation{
+			TraceID:  "trace-id",
+			Input:    []interface{}{langfuse.AudioPart(bytes.Repeat([]byte("x"), 20), "mp3"), invalid},
+			Metadata: map[string]interface{}{"export": export},
+		}})
+		body := flush()[0]["body"].(map[string]interface{})
+		parts := body["input"].([]interface{})
+		if data := parts[0].(map[string]interface{})["input_audio"].(map[string]interface{})["data"]; data != "[removed audio/mp3 20 bytes]" {
+			t.Errorf("expected the audio to be removed, got %v", data)
+		}
+		if data := parts[1].(map[string]interface{})["input_audio"].(map[string]interface{})["data"]; data != "not base64 at all!" {
+			t.Errorf("expected audio that can't be decoded to be kept, got %v", data)
+		}
+		if data := body["metadata"].(map[string]interface{})["export"].(map[string]interface{})["data"]; data != export["data"] {
+			t.Errorf("expected values that aren't audio parts to be kept, got %v", data)
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wepala/langfuse-go/api"
+)
+
+// DatasetItemOptions controls how a dataset item is built from production data
+type DatasetItemOptions struct {
+	// ID of the dataset item. Items are upserted on id so reusing an id updates the existing item
+	ID string
+	// ExpectedOutput overrides the output that was recorded in production. When nil the recorded output is used
+	ExpectedOutput interface{}
+}
+
+// DatasetItemFromObservation fetches an observation and adds it to the dataset as a test case, linking the item back
+// to the observation and its trace
+func (l *LangFuse) DatasetItemFromObservation(ctxt context.Context, datasetName string, observationID string, opts *DatasetItemOptions) (*api.DatasetItem, error) {
+	if datasetName == "" {
+		return nil, errors.New("dataset name is required")
+	}
+	if observationID == "" {
+		return nil, errors.New("observation id is required")
+	}
+
+	observation, err := l.client.Observations.Get(ctxt, observationID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving observation %s: %w", observationID, err)
+	}
+
+	request := &api.CreateDatasetItemRequest{
+		DatasetName:         datasetName,
+		Input:               deref(observation.Input),
+		SourceObservationId: api.String(observation.Id),
+		SourceTraceId:       observation.TraceId,
+	}
+	applyDatasetItemOptions(request, observation.Output, opts)
+
+	return l.client.Datasetitems.Create(ctxt, request)
+}
+
+// DatasetItemFromTrace fetches a trace and adds it to the dataset as a test case, linking the item back to the trace
+func (l *LangFuse) DatasetItemFromTrace(ctxt context.Context, datasetName string, traceID string, opts *DatasetItemOptions) (*api.DatasetItem, error) {
+	if datasetName == "" {
+		return nil, errors.New("dataset name is required")
+	}
+	if traceID == "" {
+		return nil, errors.New("trace id is required")
+	}
+
+	trace, err := l.client.Trace.Get(ctxt, traceID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving trace %s: %w", traceID, err)
+	}
+
+	request := &api.CreateDatasetItemRequest{
+		DatasetName:   datasetName,
+		Input:         deref(trace.Input),
+		SourceTraceId: api.String(trace.Id),
+	}
+	applyDatasetItemOptions(request, trace.Output, opts)
+
+	return l.client.Datasetitems.Create(ctxt, request)
+}
+
+func applyDatasetItemOptions(request *api.CreateDatasetItemRequest, output *interface{}, opts *DatasetItemOptions) {
+	if opts != nil && opts.ID != "" {
+		request.Id = api.String(opts.ID)
+	}
+	if opts != nil && opts.ExpectedOutput != nil {
+		request.ExpectedOutput = &opts.ExpectedOutput
+		return
+	}
+	request.ExpectedOutput = output
+}
+
+func deref(value *interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -0,0 +1,121 @@
+package langfuse_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestLangFuse_DatasetItemFromObservation(t *testing.T) {
+	t.Run("should create a dataset item from the observation input and output", func(t *testing.T) {
+		var payload map[string]interface{}
+		httpClient := NewTestClient(func(req *http.Request) *http.Response {
+			switch req.URL.Path {
+			case "/api/public/observations/observation-id":
+				return NewJsonResponse(http.StatusOK, map[string]interface{}{
+					"id":        "observation-id",
+					"traceId":   "trace-id",
+					"type":      "GENERATION",
+					"startTime": "2024-01-01T00:00:00Z",
+					"input":     "what is the capital of France?",
+					"output":    "Berlin",
+				})
+			case "/api/public/dataset-items":
+				_ = json.NewDecoder(req.Body).Decode(&payload)
+				return NewJsonResponse(http.StatusOK, map[string]interface{}{"id": "item-id", "datasetId": "dataset-id"})
+			}
+			t.Errorf("unexpected request to %s", req.URL.Path)
+			return NewStringResponse(http.StatusNotFound, "")
+		})
+		sdk := langfuse.New(context.TODO(), langfuse.Options{HttpClient: httpClient})
+		item, err := sdk.DatasetItemFromObservation(context.TODO(), "regressions", "observation-id", &langfuse.DatasetItemOptions{
+			ExpectedOutput: "Paris",
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if item == nil || item.Id != "item-id" {
+			t.Fatalf("expected dataset item to be returned")
+		}
+		if payload["datasetName"] != "regressions" {
+			t.Errorf("expected datasetName to be %s, got %v", "regressions", payload["datasetName"])
+		}
+		if payload["input"] != "what is the capital of France?" {
+			t.Errorf("expected input to be copied from the observation, got %v", payload["input"])
+		}
+		if payload["expectedOutput"] != "Paris" {
+			t.Errorf("expected expectedOutput to be %s, got %v", "Paris", payload["expectedOutput"])
+		}
+		if payload["sourceObservationId"] != "observation-id" {
+			t.Errorf("expected sourceObservationId to be %s, got %v", "observation-id", payload["sourceObservationId"])
+		}
+		if payload["sourceTraceId"] != "trace-id" {
+			t.Errorf("expected sourceTraceId to be %s, got %v", "trace-id", payload["sourceTraceId"])
+		}
+	})
+	t.Run("should return an error if the observation could not be retrieved", func(t *testing.T) {
+		httpClient := NewTestClient(func(req *http.Request) *http.Response {
+			if req.URL.Path == "/api/public/dataset-items" {
+				t.Errorf("expected dataset item not to be created")
+			}
+			return NewJsonResponse(http.StatusNotFound, map[string]interface{}{"message": "not found"})
+		})
+		sdk := langfuse.New(context.TODO(), langfuse.Options{HttpClient: httpClient})
+		item, err := sdk.DatasetItemFromObservation(context.TODO(), "regressions", "observation-id", nil)
+		if err == nil {
+			t.Errorf("expected an error to be returned")
+		}
+		if item != nil {
+			t.Errorf("expected no dataset item to be returned")
+		}
+	})
+}
+
+func TestLangFuse_DatasetItemFromTrace(t *testing.T) {
+	t.Run("should use the trace output as the expected output if none is provided", func(t *testing.T) {
+		var payload map[string]interface{}
+		httpClient := NewTestClient(func(req *http.Request) *http.Response {
+			switch req.URL.Path {
+			case "/api/public/traces/trace-id":
+				return NewJsonResponse(http.StatusOK, map[string]interface{}{
+					"id":        "trace-id",
+					"timestamp": "2024-01-01T00:00:00Z",
+					"input":     "hello",
+					"output":    "world",
+				})
+			case "/api/public/dataset-items":
+				_ = json.NewDecoder(req.Body).Decode(&payload)
+				return NewJsonResponse(http.StatusOK, map[string]interface{}{"id": "item-id", "datasetId": "dataset-id"})
+			}
+			t.Errorf("unexpected request to %s", req.URL.Path)
+			return NewStringResponse(http.StatusNotFound, "")
+		})
+		sdk := langfuse.New(context.TODO(), langfuse.Options{HttpClient: httpClient})
+		_, err := sdk.DatasetItemFromTrace(context.TODO(), "regressions", "trace-id", &langfuse.DatasetItemOptions{ID: "item-id"})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if payload["expectedOutput"] != "world" {
+			t.Errorf("expected expectedOutput to be %s, got %v", "world", payload["expectedOutput"])
+		}
+		if payload["sourceTraceId"] != "trace-id" {
+			t.Errorf("expected sourceTraceId to be %s, got %v", "trace-id", payload["sourceTraceId"])
+		}
+		if payload["id"] != "item-id" {
+			t.Errorf("expected id to be %s, got %v", "item-id", payload["id"])
+		}
+		if _, ok := payload["sourceObservationId"]; ok {
+			t.Errorf("expected sourceObservationId not to be set")
+		}
+	})
+	t.Run("should require a dataset name", func(t *testing.T) {
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: &EventManagerMock{}})
+		_, err := sdk.DatasetItemFromTrace(context.TODO(), "", "trace-id", nil)
+		if err == nil {
+			t.Errorf("expected an error to be returned")
+		}
+	})
+}
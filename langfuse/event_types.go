@@ -1,6 +1,7 @@
 package langfuse
 
 const TRACE_CREATE = "trace-create"
+const OBSERVATION_CREATE = "observation-create"
 const OBSERVATION_UPDATE = "observation-update"
 const SPAN_CREATE = "span-create"
 const SPAN_UPDATE = "span-update"
@@ -9,3 +10,4 @@ const EVENT_UPDATE = "event-update"
 const GENERATION_CREATE = "generation-create"
 const GENERATION_UPDATE = "generation-update"
 const SCORE_CREATE = "score-create"
+const SDK_LOG = "sdk-log"
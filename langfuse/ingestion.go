@@ -0,0 +1,522 @@
+package langfuse
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wepala/langfuse-go/api"
+)
+
+// newIngestionEvent builds the typed ingestion event for the sdk's observation types. ok is false if the value isn't
+// one of the sdk types
+func newIngestionEvent(id string, eventType string, event interface{}, timestamp time.Time) (ingestionEvent *api.IngestionEvent, ok bool, err error) {
+	ts := timestamp.UTC().Format(time.RFC3339Nano)
+	//the event bodies are a snapshot of the observation so hold the observation's lock while they're built
+	if observation, ok := event.(interface{ locker() *sync.RWMutex }); ok {
+		l := observation.locker()
+		l.RLock()
+		defer l.RUnlock()
+	}
+	switch e := event.(type) {
+	case *api.IngestionEvent:
+		return e, true, nil
+	case *Trace:
+		return traceEvent(id, ts, e), true, nil
+	case Trace:
+		return traceEvent(id, ts, &e), true, nil
+	case *Span:
+		switch eventType {
+		case SPAN_CREATE:
+			return api.NewIngestionEventFromSpanCreate(&api.CreateSpanEvent{Id: id, Timestamp: ts, Body: createSpanBody(e)}), true, nil
+		case SPAN_UPDATE:
+			return api.NewIngestionEventFromSpanUpdate(&api.UpdateSpanEvent{Id: id, Timestamp: ts, Body: updateSpanBody(e)}), true, nil
+		}
+	case *Generation:
+		switch eventType {
+		case GENERATION_CREATE:
+			body, err := createGenerationBody(e)
+			if err != nil {
+				return nil, true, err
+			}
+			return api.NewIngestionEventFromGenerationCreate(&api.CreateGenerationEvent{Id: id, Timestamp: ts, Body: body}), true, nil
+		case GENERATION_UPDATE:
+			body, err := createGenerationBody(e)
+			if err != nil {
+				return nil, true, err
+			}
+			return api.NewIngestionEventFromGenerationUpdate(&api.UpdateGenerationEvent{Id: id, Timestamp: ts, Body: updateGenerationBody(e.ID, body)}), true, nil
+		}
+	case *Event:
+		switch eventType {
+		case EVENT_CREATE:
+			return api.NewIngestionEventFromEventCreate(&api.CreateEventEvent{Id: id, Timestamp: ts, Body: createEventBody(e)}), true, nil
+		case EVENT_UPDATE:
+			//the api doesn't have an event update so events are updated as observations
+			return api.NewIngestionEventFromObservationUpdate(&api.UpdateObservationEvent{Id: id, Timestamp: ts, Body: updateEventBody(e)}), true, nil
+		}
+	case *Score:
+		if eventType == SCORE_CREATE {
+			return api.NewIngestionEventFromScoreCreate(&api.ScoreEvent{Id: id, Timestamp: ts, Body: scoreBody(e)}), true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+func traceEvent(id string, timestamp string, t *Trace) *api.IngestionEvent {
+	return api.NewIngestionEventFromTraceCreate(&api.TraceEvent{
+		Id:        id,
+		Timestamp: timestamp,
+		Body: &api.TraceBody{
+			Id:        optionalString(t.ID),
+			Name:      optionalString(t.Name),
+			UserId:    optionalString(t.UserID),
+			Input:     optionalCopy(t.Input),
+			Output:    optionalCopy(t.Output),
+			SessionId: optionalString(t.SessionID),
+			Release:   optionalString(t.Release),
+			Version:   optionalString(t.Version),
+			Metadata:  optionalMetadata(t.Metadata),
+			Tags:      t.Tags,
+			Public:    optionalBool(t.Public),
+		},
+	})
+}
+
+func createSpanBody(s *Span) *api.CreateSpanBody {
+	return &api.CreateSpanBody{
+		Id:                  optionalString(s.ID),
+		TraceId:             optionalString(s.TraceID),
+		Name:                optionalString(s.Name),
+		StartTime:           optionalTime(s.StartTime),
+		EndTime:             s.EndTime,
+		Metadata:            optionalMetadata(s.Metadata),
+		Input:               optionalCopy(s.Input),
+		Output:              optionalCopy(s.Output),
+		Level:               optionalLevel(s.Level),
+		StatusMessage:       optionalString(s.StatusMessage),
+		ParentObservationId: optionalString(s.ParentID),
+		Version:             optionalString(s.Version),
+	}
+}
+
+func updateEventBody(e *Event) *api.ObservationBody {
+	body := createEventBody(e)
+	return &api.ObservationBody{
+		Id:                  body.Id,
+		TraceId:             body.TraceId,
+		Type:                api.ObservationTypeEvent,
+		Name:                body.Name,
+		StartTime:           body.StartTime,
+		Metadata:            body.Metadata,
+		Input:               body.Input,
+		Output:              body.Output,
+		Level:               body.Level,
+		StatusMessage:       body.StatusMessage,
+		ParentObservationId: body.ParentObservationId,
+		Version:             body.Version,
+	}
+}
+
+func updateSpanBody(s *Span) *api.UpdateSpanBody {
+	body := createSpanBody(s)
+	return &api.UpdateSpanBody{
+		Id:                  s.ID,
+		TraceId:             body.TraceId,
+		Name:                body.Name,
+		StartTime:           body.StartTime,
+		EndTime:             body.EndTime,
+		Metadata:            body.Metadata,
+		Input:               body.Input,
+		Output:              body.Output,
+		Level:               body.Level,
+		StatusMessage:       body.StatusMessage,
+		ParentObservationId: body.ParentObservationId,
+		Version:             body.Version,
+	}
+}
+
+func createGenerationBody(g *Generation) (*api.CreateGenerationBody, error) {
+	body := &api.CreateGenerationBody{
+		Id:                  optionalString(g.ID),
+		TraceId:             optionalString(g.TraceID),
+		Name:                optionalString(g.Name),
+		StartTime:           optionalTime(g.StartTime),
+		EndTime:             g.EndTime,
+		CompletionStartTime: optionalTime(g.CompletionStartTime),
+		Metadata:            optionalMetadata(g.Metadata),
+		Input:               optionalCopy(g.Input),
+		Output:              optionalCopy(g.Output),
+		Level:               optionalLevel(g.Level),
+		StatusMessage:       optionalString(g.StatusMessage),
+		ParentObservationId: optionalString(g.ParentID),
+		Version:             optionalString(g.Version),
+		Model:               optionalString(g.Model),
+		PromptName:          optionalString(g.PromptName),
+	}
+	if g.PromptVersion != "" {
+		version, err := strconv.Atoi(g.PromptVersion)
+		if err != nil {
+			return nil, fmt.Errorf("prompt version must be a number, got %s", g.PromptVersion)
+		}
+		body.PromptVersion = &version
+	}
+	if len(g.ModelParameters) > 0 {
+		body.ModelParameters = make(map[string]*api.MapValue, len(g.ModelParameters))
+		for key, value := range g.ModelParameters {
+			mapValue, err := newMapValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid model parameter %s: %w", key, err)
+			}
+			body.ModelParameters[key] = mapValue
+		}
+	}
+	if len(g.Usage) > 0 {
+		usage, err := newIngestionUsage(g.Usage)
+		if err != nil {
+			return nil, err
+		}
+		body.Usage = usage
+	}
+	return body, nil
+}
+
+func updateGenerationBody(id string, body *api.CreateGenerationBody) *api.UpdateGenerationBody {
+	return &api.UpdateGenerationBody{
+		Id:                  id,
+		TraceId:             body.TraceId,
+		Name:                body.Name,
+		StartTime:           body.StartTime,
+		EndTime:             body.EndTime,
+		CompletionStartTime: body.CompletionStartTime,
+		Metadata:            body.Metadata,
+		Input:               body.Input,
+		Output:              body.Output,
+		Level:               body.Level,
+		StatusMessage:       body.StatusMessage,
+		ParentObservationId: body.ParentObservationId,
+		Version:             body.Version,
+		Model:               body.Model,
+		ModelParameters:     body.ModelParameters,
+		Usage:               body.Usage,
+		PromptName:          body.PromptName,
+		PromptVersion:       body.PromptVersion,
+	}
+}
+
+func createEventBody(e *Event) *api.CreateEventBody {
+	return &api.CreateEventBody{
+		Id:                  optionalString(e.ID),
+		TraceId:             optionalString(e.TraceID),
+		Name:                optionalString(e.Name),
+		StartTime:           optionalTime(e.StartTime),
+		Metadata:            optionalMetadata(e.Metadata),
+		Input:               optionalCopy(e.Input),
+		Output:              optionalCopy(e.Output),
+		Level:               optionalLevel(e.Level),
+		StatusMessage:       optionalString(e.StatusMessage),
+		ParentObservationId: optionalString(e.ParentID),
+		Version:             optionalString(e.Version),
+	}
+}
+
+func scoreBody(s *Score) *api.ScoreBody {
+	body := &api.ScoreBody{
+		Id:            optionalString(s.ID),
+		TraceId:       s.TraceID,
+		Name:          s.Name,
+		Value:         api.NewCreateScoreValueFromDouble(s.Value),
+		ObservationId: optionalString(s.ObservationId),
+		Comment:       optionalString(s.Comment),
+	}
+	if s.DataType == SCORE_CATEGORICAL || (s.DataType == "" && s.StringValue != "") {
+		body.Value = api.NewCreateScoreValueFromString(s.StringValue)
+	}
+	if s.DataType != "" {
+		dataType := api.ScoreDataType(s.DataType)
+		body.DataType = &dataType
+	}
+	return body
+}
+
+func newMapValue(value interface{}) (*api.MapValue, error) {
+	switch v := value.(type) {
+	case nil:
+		return api.NewMapValueFromStringOptional(nil), nil
+	case string:
+		return api.NewMapValueFromStringOptional(&v), nil
+	case bool:
+		return api.NewMapValueFromBooleanOptional(&v), nil
+	case int:
+		return api.NewMapValueFromIntegerOptional(&v), nil
+	case int32:
+		return api.NewMapValueFromIntegerOptional(api.Int(int(v))), nil
+	case int64:
+		return api.NewMapValueFromIntegerOptional(api.Int(int(v))), nil
+	case float32:
+		return api.NewMapValueFromDoubleOptional(api.Float64(float64(v))), nil
+	case float64:
+		return api.NewMapValueFromDoubleOptional(&v), nil
+	case []string:
+		return api.NewMapValueFromStringList(v), nil
+	}
+	return nil, fmt.Errorf("unsupported type %T", value)
+}
+
+// newIngestionUsage accepts either the langfuse usage keys (input, output, total, unit) or the openai keys
+// (promptTokens, completionTokens, totalTokens)
+func newIngestionUsage(usage map[string]interface{}) (*api.IngestionUsage, error) {
+	var err error
+	integer := func(key string) *int {
+		value, ok := usage[key]
+		if !ok || value == nil || err != nil {
+			return nil
+		}
+		var i int
+		if i, err = toInt(value); err != nil {
+			err = fmt.Errorf("invalid usage %s: %w", key, err)
+			return nil
+		}
+		return &i
+	}
+	double := func(key string) *float64 {
+		value, ok := usage[key]
+		if !ok || value == nil || err != nil {
+			return nil
+		}
+		var f float64
+		if f, err = toFloat(value); err != nil {
+			err = fmt.Errorf("invalid usage %s: %w", key, err)
+			return nil
+		}
+		return &f
+	}
+
+	_, hasPromptTokens := usage["promptTokens"]
+	_, hasCompletionTokens := usage["completionTokens"]
+	_, hasTotalTokens := usage["totalTokens"]
+	if hasPromptTokens || hasCompletionTokens || hasTotalTokens {
+		openAiUsage := &api.OpenAiUsage{
+			PromptTokens:     integer("promptTokens"),
+			CompletionTokens: integer("completionTokens"),
+			TotalTokens:      integer("totalTokens"),
+		}
+		return api.NewIngestionUsageFromOpenAiUsage(openAiUsage), err
+	}
+
+	value := &api.Usage{
+		Input:      integer("input"),
+		Output:     integer("output"),
+		Total:      integer("total"),
+		InputCost:  double("inputCost"),
+		OutputCost: double("outputCost"),
+		TotalCost:  double("totalCost"),
+	}
+	if unit, ok := usage["unit"]; ok && unit != nil {
+		modelUsageUnit, unitErr := api.NewModelUsageUnitFromString(fmt.Sprintf("%v", unit))
+		if unitErr != nil {
+			return nil, unitErr
+		}
+		value.Unit = &modelUsageUnit
+	}
+	return api.NewIngestionUsageFromUsage(value), err
+}
+
+func toInt(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int32:
+		return int(v), nil
+	case int64:
+		return int(v), nil
+	case float32:
+		return int(v), nil
+	case float64:
+		return int(v), nil
+	}
+	return 0, fmt.Errorf("expected a number, got %T", value)
+}
+
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("expected a number, got %T", value)
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// optionalBool only sends true so updating a trace doesn't reset a flag that was set by an earlier event
+func optionalBool(value bool) *bool {
+	if !value {
+		return nil
+	}
+	return &value
+}
+
+func optionalTime(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+	return &value
+}
+
+func optionalValue(value interface{}) *interface{} {
+	if value == nil {
+		return nil
+	}
+	return &value
+}
+
+// optionalMetadata copies the metadata so keys added to the observation, or to maps nested in it, after it's enqueued
+// aren't sent
+func optionalMetadata(metadata map[string]interface{}) *interface{} {
+	if metadata == nil {
+		return nil
+	}
+	return optionalValue(copyValue(metadata))
+}
+
+// optionalCopy copies the input or output so changes the caller makes after the observation is enqueued aren't sent
+func optionalCopy(value interface{}) *interface{} {
+	if value == nil {
+		return nil
+	}
+	return optionalValue(copyValue(value))
+}
+
+// copyValue deep copies the maps and slices json values are made of. Other maps, slices, structs and pointers are
+// copied through json so they can't be changed while the batch is being sent. Other values are shared
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = copyValue(item)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyValue(item)
+		}
+		return copied
+	case []map[string]interface{}:
+		copied := make([]map[string]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copyValue(item).(map[string]interface{})
+		}
+		return copied
+	case []string:
+		return append([]string(nil), v...)
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct, reflect.Ptr, reflect.Interface:
+		data, err := json.Marshal(value)
+		if err != nil {
+			//the error is returned when the batch is sent
+			return value
+		}
+		var copied interface{}
+		if err = json.Unmarshal(data, &copied); err != nil {
+			return value
+		}
+		return copied
+	}
+	return value
+}
+
+func optionalLevel(level string) *api.ObservationLevel {
+	if level == "" {
+		return nil
+	}
+	observationLevel := api.ObservationLevel(level)
+	return &observationLevel
+}
+
+// eventFields are the fields of an event body that hold user data
+type eventFields struct {
+	Input         *interface{}
+	Output        *interface{}
+	Metadata      *interface{}
+	StatusMessage *string
+	UserID        *string
+}
+
+// transformEvent returns a copy of the event with the fields changed by fn. The event itself isn't changed since it
+// may be shared with the caller
+func transformEvent(e *api.IngestionEvent, fn func(fields *eventFields)) *api.IngestionEvent {
+	transformed := *e
+	switch {
+	case e.TraceCreate != nil && e.TraceCreate.Body != nil:
+		event, body := *e.TraceCreate, *e.TraceCreate.Body
+		fields := &eventFields{Input: body.Input, Output: body.Output, Metadata: body.Metadata, UserID: body.UserId}
+		fn(fields)
+		body.Input, body.Output, body.Metadata, body.UserId = fields.Input, fields.Output, fields.Metadata, fields.UserID
+		event.Body = &body
+		transformed.TraceCreate = &event
+	case e.SpanCreate != nil && e.SpanCreate.Body != nil:
+		event, body := *e.SpanCreate, *e.SpanCreate.Body
+		body.Input, body.Output, body.Metadata, body.StatusMessage = transformFields(fn, body.Input, body.Output, body.Metadata, body.StatusMessage)
+		event.Body = &body
+		transformed.SpanCreate = &event
+	case e.SpanUpdate != nil && e.SpanUpdate.Body != nil:
+		event, body := *e.SpanUpdate, *e.SpanUpdate.Body
+		body.Input, body.Output, body.Metadata, body.StatusMessage = transformFields(fn, body.Input, body.Output, body.Metadata, body.StatusMessage)
+		event.Body = &body
+		transformed.SpanUpdate = &event
+	case e.GenerationCreate != nil && e.GenerationCreate.Body != nil:
+		event, body := *e.GenerationCreate, *e.GenerationCreate.Body
+		body.Input, body.Output, body.Metadata, body.StatusMessage = transformFields(fn, body.Input, body.Output, body.Metadata, body.StatusMessage)
+		event.Body = &body
+		transformed.GenerationCreate = &event
+	case e.GenerationUpdate != nil && e.GenerationUpdate.Body != nil:
+		event, body := *e.GenerationUpdate, *e.GenerationUpdate.Body
+		body.Input, body.Output, body.Metadata, body.StatusMessage = transformFields(fn, body.Input, body.Output, body.Metadata, body.StatusMessage)
+		event.Body = &body
+		transformed.GenerationUpdate = &event
+	case e.EventCreate != nil && e.EventCreate.Body != nil:
+		event, body := *e.EventCreate, *e.EventCreate.Body
+		body.Input, body.Output, body.Metadata, body.StatusMessage = transformFields(fn, body.Input, body.Output, body.Metadata, body.StatusMessage)
+		event.Body = &body
+		transformed.EventCreate = &event
+	case e.ObservationCreate != nil && e.ObservationCreate.Body != nil:
+		event, body := *e.ObservationCreate, *e.ObservationCreate.Body
+		body.Input, body.Output, body.Metadata, body.StatusMessage = transformFields(fn, body.Input, body.Output, body.Metadata, body.StatusMessage)
+		event.Body = &body
+		transformed.ObservationCreate = &event
+	case e.ObservationUpdate != nil && e.ObservationUpdate.Body != nil:
+		event, body := *e.ObservationUpdate, *e.ObservationUpdate.Body
+		body.Input, body.Output, body.Metadata, body.StatusMessage = transformFields(fn, body.Input, body.Output, body.Metadata, body.StatusMessage)
+		event.Body = &body
+		transformed.ObservationUpdate = &event
+	}
+	return &transformed
+}
+
+func transformFields(fn func(fields *eventFields), input, output, metadata *interface{}, statusMessage *string) (*interface{}, *interface{}, *interface{}, *string) {
+	fields := &eventFields{Input: input, Output: output, Metadata: metadata, StatusMessage: statusMessage}
+	fn(fields)
+	return fields.Input, fields.Output, fields.Metadata, fields.StatusMessage
+}
@@ -0,0 +1,155 @@
+package langfuse_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/wepala/langfuse-go/api"
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+// captureBatch returns an sdk and a flush function that sends the queued events and returns what was sent
+func captureBatch(t *testing.T) (*langfuse.LangFuse, func() []map[string]interface{}) {
+	var events []map[string]interface{}
+	sent := make(chan bool, 1)
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		defer func() { sent <- true }()
+		var payload struct {
+			Batch []map[string]interface{} `json:"batch"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			t.Fatalf("expected payload to be decoded, got %s", err)
+		}
+		events = append(events, payload.Batch...)
+		return NewJsonResponse(http.StatusOK, map[string]interface{}{})
+	})
+	sdk := langfuse.New(context.TODO(), langfuse.Options{HttpClient: httpClient, TotalQueues: 1, MaxBatchSize: 20})
+	return sdk, func() []map[string]interface{} {
+		sdk.EventManager().Flush(context.TODO())
+		select {
+		case <-sent:
+		case <-time.After(time.Second):
+			t.Fatalf("expected batch to be sent")
+		}
+		return events
+	}
+}
+
+func TestBatchEventManager_Enqueue_TypedEvents(t *testing.T) {
+	t.Run("should send the sdk observations as typed ingestion events", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id", Name: "chat"}})
+		generation, err := trace.Generation(&langfuse.Generation{
+			Model:           "gpt-4",
+			ModelParameters: map[string]interface{}{"temperature": 0.7, "maxTokens": 100, "stream": false, "stop": []string{"\n"}},
+			Usage:           map[string]interface{}{"promptTokens": 10, "completionTokens": 5},
+			PromptVersion:   "2",
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		_ = generation.End()
+		_, _ = trace.Event(&langfuse.Event{BasicObservation: langfuse.BasicObservation{Name: "retrieved"}})
+		events := flush()
+		if len(events) != 4 {
+			t.Fatalf("expected %d events to be sent, got %d", 4, len(events))
+		}
+		types := []string{langfuse.TRACE_CREATE, langfuse.GENERATION_CREATE, langfuse.GENERATION_UPDATE, langfuse.EVENT_CREATE}
+		for i, eventType := range types {
+			if events[i]["type"] != eventType {
+				t.Errorf("expected event %d to be %s, got %v", i, eventType, events[i]["type"])
+			}
+			if _, err := time.Parse(time.RFC3339Nano, events[i]["timestamp"].(string)); err != nil {
+				t.Errorf("expected timestamp to be set, got %s", err)
+			}
+		}
+		body := events[1]["body"].(map[string]interface{})
+		if body["promptVersion"] != float64(2) {
+			t.Errorf("expected promptVersion to be sent as a number, got %v", body["promptVersion"])
+		}
+		parameters := body["modelParameters"].(map[string]interface{})
+		if parameters["temperature"] != 0.7 || parameters["maxTokens"] != float64(100) || parameters["stream"] != false {
+			t.Errorf("expected model parameters to keep their types, got %v", parameters)
+		}
+		usage := body["usage"].(map[string]interface{})
+		if usage["promptTokens"] != float64(10) {
+			t.Errorf("expected usage to be sent, got %v", usage)
+		}
+		if events[2]["body"].(map[string]interface{})["endTime"] == nil {
+			t.Errorf("expected generation update to have an end time")
+		}
+		eventBody := events[3]["body"].(map[string]interface{})
+		if eventBody["startTime"] == nil || eventBody["start_time"] != nil {
+			t.Errorf("expected event start time to be sent as startTime")
+		}
+	})
+	t.Run("should return an error if the prompt version is not a number", func(t *testing.T) {
+		sdk, _ := captureBatch(t)
+		_, err := sdk.Generation(context.TODO(), &langfuse.Generation{PromptVersion: "latest"})
+		if err == nil {
+			t.Errorf("expected an error to be returned")
+		}
+	})
+	t.Run("should accept prebuilt ingestion events", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		err := sdk.EventManager().Enqueue("event-id", langfuse.SCORE_CREATE, api.NewIngestionEventFromScoreCreate(&api.ScoreEvent{
+			Id:        "event-id",
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Body:      &api.ScoreBody{TraceId: "trace-id", Name: "quality", Value: api.NewCreateScoreValueFromDouble(1)},
+		}))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		events := flush()
+		if len(events) != 1 || events[0]["type"] != langfuse.SCORE_CREATE {
+			t.Errorf("expected score event to be sent")
+		}
+	})
+	t.Run("should reject values that aren't ingestion events", func(t *testing.T) {
+		sdk, _ := captureBatch(t)
+		if err := sdk.EventManager().Enqueue("event-id", "test", map[string]interface{}{}); err == nil {
+			t.Errorf("expected an error to be returned")
+		}
+	})
+	t.Run("should only send public when it's set", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}, Public: true})
+		//traces are upserted by id so this updates the trace created above
+		if _, err := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: trace.ID, Name: "renamed"}}); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		events := flush()
+		if len(events) != 2 {
+			t.Fatalf("expected %d events to be sent, got %d", 2, len(events))
+		}
+		if events[0]["body"].(map[string]interface{})["public"] != true {
+			t.Errorf("expected the trace to be created as public")
+		}
+		if _, ok := events[1]["body"].(map[string]interface{})["public"]; ok {
+			t.Errorf("expected the update not to send public")
+		}
+	})
+}
+
+func BenchmarkBatchEventManager_Enqueue(b *testing.B) {
+	eventManager := langfuse.NewBatchEventManager(nil, 1, b.N+1)
+	generation := &langfuse.Generation{
+		BasicObservation: langfuse.BasicObservation{
+			ID:       "generation-id",
+			TraceID:  "trace-id",
+			Input:    []map[string]interface{}{{"role": "user", "content": "hello"}},
+			Metadata: map[string]interface{}{"key": "value"},
+		},
+		Model:           "gpt-4",
+		ModelParameters: map[string]interface{}{"temperature": 0.7},
+		StartTime:       time.Now(),
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = eventManager.Enqueue("", langfuse.GENERATION_CREATE, generation)
+	}
+}
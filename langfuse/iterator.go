@@ -0,0 +1,234 @@
+package langfuse
+
+import (
+	"context"
+
+	"github.com/wepala/langfuse-go/api"
+)
+
+// PageFetcher retrieves a single page of results from a list endpoint. Pages start at 1
+type PageFetcher[T any] func(ctxt context.Context, page int) ([]T, *api.UtilsMetaResponse, error)
+
+type IteratorOptions struct {
+	// Prefetch is the number of pages to fetch concurrently ahead of the page being read. When 0 pages are fetched
+	// one at a time as they are needed
+	Prefetch int
+}
+
+type pageResult[T any] struct {
+	items []T
+	meta  *api.UtilsMetaResponse
+	err   error
+}
+
+// Iterator lazily walks every page of a list endpoint. Call Next until it returns false then check Err
+//
+//	it := sdk.ListTraces(ctxt, &api.TraceListRequest{}, nil)
+//	defer it.Close()
+//	for it.Next() {
+//		trace := it.Current()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type Iterator[T any] struct {
+	ctxt       context.Context
+	cancel     context.CancelFunc
+	fetch      PageFetcher[T]
+	inFlight   int
+	nextPage   int
+	totalPages int
+	pending    []chan pageResult[T]
+	items      []T
+	current    T
+	err        error
+	done       bool
+}
+
+// NewIterator creates an iterator that starts at the given page and calls fetch for each page until the total pages
+// reported by the api have been read or an empty page is returned
+func NewIterator[T any](ctxt context.Context, startPage int, fetch PageFetcher[T], opts *IteratorOptions) *Iterator[T] {
+	if ctxt == nil {
+		ctxt = context.Background()
+	}
+	if startPage < 1 {
+		startPage = 1
+	}
+	inFlight := 1
+	if opts != nil && opts.Prefetch > 0 {
+		inFlight = opts.Prefetch + 1
+	}
+	tctxt, cancel := context.WithCancel(ctxt)
+	return &Iterator[T]{
+		ctxt:       tctxt,
+		cancel:     cancel,
+		fetch:      fetch,
+		inFlight:   inFlight,
+		nextPage:   startPage,
+		totalPages: -1,
+	}
+}
+
+// Next advances the iterator to the next item, fetching the next page if necessary
+func (i *Iterator[T]) Next() bool {
+	if i.done {
+		return false
+	}
+	for len(i.items) == 0 {
+		if !i.nextResults() {
+			i.Close()
+			return false
+		}
+	}
+	i.current = i.items[0]
+	i.items = i.items[1:]
+	return true
+}
+
+// Current returns the item the iterator is positioned on
+func (i *Iterator[T]) Current() T {
+	return i.current
+}
+
+// Err returns the first error encountered while fetching pages, including context cancellation
+func (i *Iterator[T]) Err() error {
+	return i.err
+}
+
+// Close stops the iterator and cancels any pages that are still being fetched
+func (i *Iterator[T]) Close() {
+	i.done = true
+	i.pending = nil
+	i.cancel()
+}
+
+// All reads the remaining items into a slice
+func (i *Iterator[T]) All() ([]T, error) {
+	var items []T
+	for i.Next() {
+		items = append(items, i.Current())
+	}
+	return items, i.Err()
+}
+
+func (i *Iterator[T]) nextResults() bool {
+	if err := i.ctxt.Err(); err != nil {
+		i.err = err
+		return false
+	}
+	i.schedule()
+	if len(i.pending) == 0 {
+		return false
+	}
+	var result pageResult[T]
+	select {
+	case <-i.ctxt.Done():
+		i.err = i.ctxt.Err()
+		return false
+	case result = <-i.pending[0]:
+		i.pending = i.pending[1:]
+	}
+	if result.err != nil {
+		i.err = result.err
+		return false
+	}
+	if result.meta != nil {
+		i.totalPages = result.meta.TotalPages
+	} else if len(result.items) == 0 {
+		//without meta information an empty page is the only indication that we've reached the end
+		i.totalPages = i.nextPage - 1
+	}
+	i.items = result.items
+	return true
+}
+
+// schedule starts fetching pages until the configured number of pages are in flight. Until the total pages are known
+// only one page is fetched at a time
+func (i *Iterator[T]) schedule() {
+	limit := i.inFlight
+	if i.totalPages < 0 {
+		limit = 1
+	}
+	for len(i.pending) < limit {
+		if i.totalPages >= 0 && i.nextPage > i.totalPages {
+			return
+		}
+		result := make(chan pageResult[T], 1)
+		go func(page int) {
+			items, meta, err := i.fetch(i.ctxt, page)
+			result <- pageResult[T]{items: items, meta: meta, err: err}
+		}(i.nextPage)
+		i.pending = append(i.pending, result)
+		i.nextPage++
+	}
+}
+
+// ListTraces returns an iterator over every trace matching the request
+func (l *LangFuse) ListTraces(ctxt context.Context, request *api.TraceListRequest, opts *IteratorOptions) *Iterator[*api.TraceWithDetails] {
+	if request == nil {
+		request = &api.TraceListRequest{}
+	}
+	return NewIterator(ctxt, startPage(request.Page), func(ctxt context.Context, page int) ([]*api.TraceWithDetails, *api.UtilsMetaResponse, error) {
+		pageRequest := *request
+		pageRequest.Page = api.Int(page)
+		response, err := l.client.Trace.List(ctxt, &pageRequest)
+		if err != nil || response == nil {
+			return nil, nil, err
+		}
+		return response.Data, response.Meta, nil
+	}, opts)
+}
+
+// ListObservations returns an iterator over every observation matching the request
+func (l *LangFuse) ListObservations(ctxt context.Context, request *api.ObservationsGetManyRequest, opts *IteratorOptions) *Iterator[*api.Observation] {
+	if request == nil {
+		request = &api.ObservationsGetManyRequest{}
+	}
+	return NewIterator(ctxt, startPage(request.Page), func(ctxt context.Context, page int) ([]*api.Observation, *api.UtilsMetaResponse, error) {
+		pageRequest := *request
+		pageRequest.Page = api.Int(page)
+		response, err := l.client.Observations.Getmany(ctxt, &pageRequest)
+		if err != nil || response == nil {
+			return nil, nil, err
+		}
+		return response.Data, response.Meta, nil
+	}, opts)
+}
+
+// ListScores returns an iterator over every score matching the request
+func (l *LangFuse) ListScores(ctxt context.Context, request *api.ScoreGetRequest, opts *IteratorOptions) *Iterator[*api.Score] {
+	if request == nil {
+		request = &api.ScoreGetRequest{}
+	}
+	return NewIterator(ctxt, startPage(request.Page), func(ctxt context.Context, page int) ([]*api.Score, *api.UtilsMetaResponse, error) {
+		pageRequest := *request
+		pageRequest.Page = api.Int(page)
+		response, err := l.client.Score.Get(ctxt, &pageRequest)
+		if err != nil || response == nil {
+			return nil, nil, err
+		}
+		return response.Data, response.Meta, nil
+	}, opts)
+}
+
+// DailyMetrics returns an iterator over the daily trace counts, usage and cost matching the request
+func (l *LangFuse) DailyMetrics(ctxt context.Context, request *api.MetricsDailyRequest, opts *IteratorOptions) *Iterator[*api.DailyMetricsDetails] {
+	if request == nil {
+		request = &api.MetricsDailyRequest{}
+	}
+	return NewIterator(ctxt, startPage(request.Page), func(ctxt context.Context, page int) ([]*api.DailyMetricsDetails, *api.UtilsMetaResponse, error) {
+		pageRequest := *request
+		pageRequest.Page = api.Int(page)
+		response, err := l.client.Metrics.Daily(ctxt, &pageRequest)
+		if err != nil || response == nil {
+			return nil, nil, err
+		}
+		return response.Data, response.Meta, nil
+	}, opts)
+}
+
+func startPage(page *int) int {
+	if page == nil {
+		return 1
+	}
+	return *page
+}
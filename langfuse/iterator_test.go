@@ -0,0 +1,163 @@
+package langfuse_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wepala/langfuse-go/api"
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestIterator_Next(t *testing.T) {
+	t.Run("should walk all pages until the total pages are read", func(t *testing.T) {
+		var calls int32
+		it := langfuse.NewIterator(context.TODO(), 1, func(ctxt context.Context, page int) ([]int, *api.UtilsMetaResponse, error) {
+			atomic.AddInt32(&calls, 1)
+			return []int{page*10 + 1, page*10 + 2}, &api.UtilsMetaResponse{Page: page, TotalPages: 3}, nil
+		}, nil)
+		items, err := it.All()
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		expected := []int{11, 12, 21, 22, 31, 32}
+		if fmt.Sprint(items) != fmt.Sprint(expected) {
+			t.Errorf("expected items to be %v, got %v", expected, items)
+		}
+		if calls != 3 {
+			t.Errorf("expected %d pages to be fetched, got %d", 3, calls)
+		}
+	})
+	t.Run("should return items in page order when prefetching", func(t *testing.T) {
+		it := langfuse.NewIterator(context.TODO(), 1, func(ctxt context.Context, page int) ([]int, *api.UtilsMetaResponse, error) {
+			return []int{page}, &api.UtilsMetaResponse{Page: page, TotalPages: 20}, nil
+		}, &langfuse.IteratorOptions{Prefetch: 4})
+		items, err := it.All()
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if len(items) != 20 {
+			t.Fatalf("expected %d items, got %d", 20, len(items))
+		}
+		for i, item := range items {
+			if item != i+1 {
+				t.Fatalf("expected item %d to be %d, got %d", i, i+1, item)
+			}
+		}
+	})
+	t.Run("should stop on an empty page when there is no meta information", func(t *testing.T) {
+		it := langfuse.NewIterator(context.TODO(), 1, func(ctxt context.Context, page int) ([]int, *api.UtilsMetaResponse, error) {
+			if page > 2 {
+				return nil, nil, nil
+			}
+			return []int{page}, nil, nil
+		}, nil)
+		items, _ := it.All()
+		if len(items) != 2 {
+			t.Errorf("expected %d items, got %d", 2, len(items))
+		}
+	})
+	t.Run("should stop and surface the error when a page fails", func(t *testing.T) {
+		it := langfuse.NewIterator(context.TODO(), 1, func(ctxt context.Context, page int) ([]int, *api.UtilsMetaResponse, error) {
+			if page == 2 {
+				return nil, nil, errors.New("boom")
+			}
+			return []int{page}, &api.UtilsMetaResponse{Page: page, TotalPages: 5}, nil
+		}, nil)
+		items, err := it.All()
+		if err == nil {
+			t.Errorf("expected an error to be returned")
+		}
+		if len(items) != 1 {
+			t.Errorf("expected %d items before the error, got %d", 1, len(items))
+		}
+	})
+	t.Run("should stop when the context is cancelled", func(t *testing.T) {
+		ctxt, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		it := langfuse.NewIterator(ctxt, 1, func(ctxt context.Context, page int) ([]int, *api.UtilsMetaResponse, error) {
+			return []int{page}, &api.UtilsMetaResponse{Page: page, TotalPages: 100}, nil
+		}, nil)
+		count := 0
+		for it.Next() {
+			count++
+			if count == 3 {
+				cancel()
+			}
+		}
+		if count != 3 {
+			t.Errorf("expected iteration to stop after %d items, got %d", 3, count)
+		}
+		if !errors.Is(it.Err(), context.Canceled) {
+			t.Errorf("expected context cancelled error, got %v", it.Err())
+		}
+	})
+}
+
+func TestLangFuse_ListTraces(t *testing.T) {
+	t.Run("should request each page with the original filters", func(t *testing.T) {
+		httpClient := NewTestClient(func(req *http.Request) *http.Response {
+			if req.URL.Query().Get("userId") != "user-id" {
+				t.Errorf("expected userId filter to be sent on every page")
+			}
+			if req.URL.Query().Get("release") != "v2" {
+				t.Errorf("expected release filter to be sent on every page")
+			}
+			page, _ := strconv.Atoi(req.URL.Query().Get("page"))
+			return NewJsonResponse(http.StatusOK, map[string]interface{}{
+				"data": []map[string]interface{}{{"id": fmt.Sprintf("trace-%d", page), "timestamp": "2024-01-01T00:00:00Z"}},
+				"meta": map[string]interface{}{"page": page, "limit": 1, "totalItems": 2, "totalPages": 2},
+			})
+		})
+		sdk := langfuse.New(context.TODO(), langfuse.Options{HttpClient: httpClient})
+		traces, err := sdk.ListTraces(context.TODO(), &api.TraceListRequest{UserId: api.String("user-id"), Release: api.String("v2")}, nil).All()
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if len(traces) != 2 || traces[0].Id != "trace-1" || traces[1].Id != "trace-2" {
+			t.Errorf("expected traces from both pages to be returned")
+		}
+	})
+}
+
+func TestLangFuse_ListObservations(t *testing.T) {
+	t.Run("should send the level, model and time window filters", func(t *testing.T) {
+		from := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+		to := from.Add(time.Hour)
+		httpClient := NewTestClient(func(req *http.Request) *http.Response {
+			query := req.URL.Query()
+			expected := map[string]string{
+				"level":         "ERROR",
+				"model":         "gpt-4",
+				"version":       "v1",
+				"fromStartTime": "2024-01-01T10:00:00Z",
+				"toStartTime":   "2024-01-01T11:00:00Z",
+			}
+			for key, value := range expected {
+				if query.Get(key) != value {
+					t.Errorf("expected %s to be %s, got %s", key, value, query.Get(key))
+				}
+			}
+			return NewJsonResponse(http.StatusOK, map[string]interface{}{
+				"data": []map[string]interface{}{},
+				"meta": map[string]interface{}{"page": 1, "limit": 50, "totalItems": 0, "totalPages": 0},
+			})
+		})
+		sdk := langfuse.New(context.TODO(), langfuse.Options{HttpClient: httpClient})
+		_, err := sdk.ListObservations(context.TODO(), &api.ObservationsGetManyRequest{
+			Level:         api.ObservationLevelError.Ptr(),
+			Model:         api.String("gpt-4"),
+			Version:       api.String("v1"),
+			FromStartTime: api.Time(from),
+			ToStartTime:   api.Time(to),
+		}, nil).All()
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+	})
+}
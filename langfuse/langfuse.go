@@ -2,7 +2,6 @@ package langfuse
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"os"
 	"time"
@@ -20,11 +19,26 @@ type Options struct {
 	Release      string       `json:"release"`
 	TotalQueues  int          `json:"total_queues"`
 	MaxBatchSize int          `json:"max_batch_size"`
+	// Sampler decides which traces are sent. All traces are sent if it's not set
+	Sampler Sampler `json:"-"`
+	// SampleErrors sends traces that weren't sampled if one of their observations has the ERROR level
+	SampleErrors bool `json:"sample_errors"`
+	// Masking masks the input, output and metadata of observations before they're queued
+	Masking *MaskingOptions `json:"-"`
+	// Limits caps the size of the input, output and metadata of observations
+	Limits *LimitOptions `json:"-"`
+	// TailSampling holds the events of each trace and only sends the traces that match its rules
+	TailSampling *TailSamplingOptions `json:"tail_sampling"`
+	// Strict checks each event against the ingestion schema when it's enqueued and returns an error if it doesn't match
+	Strict bool `json:"strict"`
 }
 
 type LangFuse struct {
 	client       *client.Client
 	eventManager EventManager
+	sampler      Sampler
+	sampleErrors bool
+	decisions    *samplingDecisions
 	Shutdown     context.CancelFunc
 }
 
@@ -49,12 +63,38 @@ func (l *LangFuse) Trace(ctxt context.Context, opts *Trace) (*Trace, error) {
 		opts.Release = os.Getenv("LANGFUSE_RELEASE")
 	}
 
-	opts.eventManager = l.eventManager
+	eventManager := l.traceEventManager(opts)
+	opts.init(eventManager)
 
-	err := l.eventManager.Enqueue("", TRACE_CREATE, opts)
+	err := eventManager.Enqueue("", TRACE_CREATE, opts)
 	return opts, err
 }
 
+// traceEventManager returns the event manager for the trace and what's created from it. Traces that aren't sampled
+// are still returned so callers don't need to check whether they were sampled. The decision is kept so updates to
+// the trace and observations created with its id get the same event manager
+func (l *LangFuse) traceEventManager(trace *Trace) EventManager {
+	if l.sampler == nil {
+		return l.eventManager
+	}
+	return l.decisions.get(trace.ID, func() EventManager {
+		if !l.sampler.Sample(trace) {
+			return &unsampledEventManager{eventManager: l.eventManager, sampleErrors: l.sampleErrors}
+		}
+		return l.eventManager
+	})
+}
+
+// observationEventManager returns the event manager for an observation or score created directly on the sdk. They
+// get the decision made when their trace was created. Traces the sdk hasn't seen are sampled by their id alone, so
+// rules that match the name, user or tags don't apply to them
+func (l *LangFuse) observationEventManager(traceID string) EventManager {
+	if traceID == "" {
+		return l.eventManager
+	}
+	return l.traceEventManager(&Trace{BasicObservation: BasicObservation{ID: traceID}})
+}
+
 func (l *LangFuse) Span(ctxt context.Context, opts *Span) (*Span, error) {
 	if opts == nil {
 		opts = &Span{}
@@ -68,8 +108,10 @@ func (l *LangFuse) Span(ctxt context.Context, opts *Span) (*Span, error) {
 		opts.StartTime = time.Now()
 	}
 
-	l.eventManager.Enqueue("", SPAN_CREATE, opts)
-	return opts, nil
+	eventManager := l.observationEventManager(opts.TraceID)
+	opts.init(eventManager)
+	err := eventManager.Enqueue("", SPAN_CREATE, opts)
+	return opts, err
 }
 
 func (l *LangFuse) Event(ctxt context.Context, opts *Event) (*Event, error) {
@@ -85,8 +127,10 @@ func (l *LangFuse) Event(ctxt context.Context, opts *Event) (*Event, error) {
 		opts.StartTime = time.Now()
 	}
 
-	l.eventManager.Enqueue("", EVENT_CREATE, opts)
-	return opts, nil
+	eventManager := l.observationEventManager(opts.TraceID)
+	opts.init(eventManager)
+	err := eventManager.Enqueue("", EVENT_CREATE, opts)
+	return opts, err
 }
 
 func (l *LangFuse) Generation(ctxt context.Context, opts *Generation) (*Generation, error) {
@@ -102,8 +146,10 @@ func (l *LangFuse) Generation(ctxt context.Context, opts *Generation) (*Generati
 		opts.StartTime = time.Now()
 	}
 
-	l.eventManager.Enqueue("", GENERATION_CREATE, opts)
-	return opts, nil
+	eventManager := l.observationEventManager(opts.TraceID)
+	opts.init(eventManager)
+	err := eventManager.Enqueue("", GENERATION_CREATE, opts)
+	return opts, err
 }
 
 func (l *LangFuse) Score(ctxt context.Context, opts *Score) (*Score, error) {
@@ -115,16 +161,14 @@ func (l *LangFuse) Score(ctxt context.Context, opts *Score) (*Score, error) {
 		opts.ID = ksuid.New().String()
 	}
 
-	if opts.TraceID == "" {
-		return nil, fmt.Errorf("trace id is required")
-	}
-
-	if opts.Name == "" {
-		return nil, fmt.Errorf("name is required")
+	if err := opts.Validate(); err != nil {
+		return nil, err
 	}
 
-	l.eventManager.Enqueue("", SCORE_CREATE, opts)
-	return opts, nil
+	eventManager := l.observationEventManager(opts.TraceID)
+	opts.init(eventManager)
+	err := eventManager.Enqueue("", SCORE_CREATE, opts)
+	return opts, err
 }
 
 func (l *LangFuse) Start(ctxt context.Context) {
@@ -133,8 +177,8 @@ func (l *LangFuse) Start(ctxt context.Context) {
 			ctxt = context.Background()
 		}
 		tctxt, cancel := context.WithCancel(ctxt)
-		if batchEventManager, ok := l.eventManager.(*BatchEventManager); ok {
-			go batchEventManager.Process(tctxt)
+		if processor, ok := l.eventManager.(interface{ Process(ctxt context.Context) }); ok {
+			go processor.Process(tctxt)
 		}
 		l.Shutdown = cancel
 	}
@@ -168,10 +212,26 @@ func New(ctxt context.Context, options Options) *LangFuse {
 		batchEventManager = NewBatchEventManager(tclient, options.TotalQueues, options.MaxBatchSize)
 		options.EventManager = batchEventManager
 	}
+	//events are masked and limited before the tail sampler holds them
+	if options.TailSampling != nil {
+		options.EventManager = NewTailSamplingEventManager(options.EventManager, *options.TailSampling)
+	}
+	if options.Limits != nil {
+		options.EventManager = newLimitEventManager(options.EventManager, *options.Limits)
+	}
+	if options.Masking != nil {
+		options.EventManager = newMaskingEventManager(options.EventManager, *options.Masking)
+	}
+	if options.Strict {
+		options.EventManager = newValidatingEventManager(options.EventManager)
+	}
 
 	lf := &LangFuse{
 		client:       tclient,
 		eventManager: options.EventManager,
+		sampler:      options.Sampler,
+		sampleErrors: options.SampleErrors,
+		decisions:    newSamplingDecisions(),
 	}
 	return lf
 }
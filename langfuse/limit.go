@@ -0,0 +1,256 @@
+package langfuse
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"time"
+	"unicode/utf8"
+)
+
+// LimitOptions are the largest sizes, in bytes of json, that the input, output and metadata of an observation can
+// be. Values over the limit are truncated, or offloaded if there is storage. A limit of 0 means there is no limit
+type LimitOptions struct {
+	MaxInputSize    int
+	MaxOutputSize   int
+	MaxMetadataSize int
+	// MaxMediaSize is the largest size of media sent as base64 data uris e.g. images in chat messages, or as
+	// input_audio parts. Larger media is offloaded if there is storage and otherwise removed. It applies even if the
+	// value is under its limit
+	MaxMediaSize int
+	// Storage stores values that are over the limit so they can be replaced with a reference instead of truncated
+	Storage Storage
+}
+
+var dataURI = regexp.MustCompile(`^data:([\w/+.-]+);base64,`)
+
+// limitEventManager limits the size of events before passing them to the next event manager
+type limitEventManager struct {
+	eventManager EventManager
+	options      LimitOptions
+}
+
+func newLimitEventManager(eventManager EventManager, options LimitOptions) *limitEventManager {
+	return &limitEventManager{
+		eventManager: eventManager,
+		options:      options,
+	}
+}
+
+func (l *limitEventManager) Enqueue(id string, eventType string, event interface{}) error {
+	ingestionEvent, ok, err := newIngestionEvent(id, eventType, event, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return l.eventManager.Enqueue(id, eventType, event)
+	}
+	return l.eventManager.Enqueue(id, eventType, transformEvent(ingestionEvent, func(fields *eventFields) {
+		fields.Input = l.limitField(fields.Input, l.options.MaxInputSize)
+		fields.Output = l.limitField(fields.Output, l.options.MaxOutputSize)
+		fields.Metadata = l.limitField(fields.Metadata, l.options.MaxMetadataSize)
+	}))
+}
+
+func (l *limitEventManager) Flush(ctxt context.Context) {
+	l.eventManager.Flush(ctxt)
+}
+
+func (l *limitEventManager) Process(ctxt context.Context) {
+	if processor, ok := l.eventManager.(interface{ Process(ctxt context.Context) }); ok {
+		processor.Process(ctxt)
+	}
+}
+
+func (l *limitEventManager) limitField(value *interface{}, limit int) *interface{} {
+	if value == nil || (limit <= 0 && l.options.MaxMediaSize <= 0) {
+		return value
+	}
+	limited := *value
+	if l.options.MaxMediaSize > 0 {
+		limited = l.limitMedia(limited)
+	}
+	if limit > 0 {
+		limited = l.limit(limited, limit)
+	}
+	return &limited
+}
+
+// limitMedia replaces data uris that are over the media limit
+func (l *limitEventManager) limitMedia(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil || (!bytes.Contains(data, []byte(";base64,")) && !bytes.Contains(data, []byte(`"`+CONTENT_INPUT_AUDIO+`"`))) {
+		return value
+	}
+	if value, err = normalize(value); err != nil {
+		return value
+	}
+	return l.replaceMedia(value)
+}
+
+func (l *limitEventManager) replaceMedia(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if len(v) <= l.options.MaxMediaSize {
+			return v
+		}
+		mimeType, data, ok := ParseDataURI(v)
+		if !ok {
+			return v
+		}
+		return l.removeMedia(data, mimeType)
+	case map[string]interface{}:
+		//audio parts have the base64 data and the format in separate fields
+		if audio, ok := v["input_audio"].(map[string]interface{}); ok && v["type"] == CONTENT_INPUT_AUDIO {
+			encoded, _ := audio["data"].(string)
+			format, _ := audio["format"].(string)
+			if len(encoded) > l.options.MaxMediaSize && audioFormats[format] {
+				if data, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+					audio["data"] = l.removeMedia(data, "audio/"+format)
+				}
+			}
+			return v
+		}
+		for key, item := range v {
+			v[key] = l.replaceMedia(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = l.replaceMedia(item)
+		}
+	}
+	return value
+}
+
+// audioFormats are the formats of input_audio parts
+var audioFormats = map[string]bool{"wav": true, "mp3": true, "flac": true, "ogg": true, "opus": true, "aac": true, "m4a": true, "webm": true, "pcm16": true}
+
+// removeMedia offloads the media if there is storage and otherwise replaces it with a description
+func (l *limitEventManager) removeMedia(data []byte, mimeType string) string {
+	if l.options.Storage != nil {
+		if reference, err := l.store(data, mimeType); err == nil {
+			return reference
+		}
+	}
+	return fmt.Sprintf("[removed %s %d bytes]", mimeType, len(data))
+}
+
+// limit first offloads the strings that are over the limit on their own e.g. base64 images, then truncates the
+// longest strings and finally replaces the whole value if it's still over the limit
+func (l *limitEventManager) limit(value interface{}, limit int) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil || len(data) <= limit {
+		return value
+	}
+	value, err = normalize(value)
+	if err != nil {
+		return value
+	}
+	if l.options.Storage != nil {
+		value = l.offloadStrings(value, limit)
+		if data, err = json.Marshal(value); err != nil || len(data) <= limit {
+			return value
+		}
+	}
+	//find the longest length strings can be that keeps the value under the limit
+	low, high := 0, limit
+	for low < high {
+		length := (low + high + 1) / 2
+		if size(truncateStrings(value, length)) <= limit {
+			low = length
+		} else {
+			high = length - 1
+		}
+	}
+	truncated := truncateStrings(value, low)
+	if data, err = json.Marshal(truncated); err != nil || len(data) <= limit {
+		return truncated
+	}
+	if data, err = json.Marshal(value); err != nil {
+		return truncated
+	}
+	if l.options.Storage != nil {
+		if reference, err := l.store(data, "application/json"); err == nil {
+			return reference
+		}
+	}
+	return truncate(string(data), limit)
+}
+
+func size(value interface{}) int {
+	data, _ := json.Marshal(value)
+	return len(data)
+}
+
+// truncateStrings returns a copy of the value with strings longer than length truncated
+func truncateStrings(value interface{}, length int) interface{} {
+	switch v := value.(type) {
+	case string:
+		return truncate(v, length)
+	case map[string]interface{}:
+		truncated := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			truncated[key] = truncateStrings(item, length)
+		}
+		return truncated
+	case []interface{}:
+		truncated := make([]interface{}, len(v))
+		for i, item := range v {
+			truncated[i] = truncateStrings(item, length)
+		}
+		return truncated
+	}
+	return value
+}
+
+func (l *limitEventManager) offloadStrings(value interface{}, limit int) interface{} {
+	switch v := value.(type) {
+	case string:
+		if len(v) <= limit {
+			return v
+		}
+		data, contentType := []byte(v), "text/plain"
+		//store images and other files sent as data uris as the file rather than the text
+		if mimeType, decoded, ok := ParseDataURI(v); ok {
+			data, contentType = decoded, mimeType
+		}
+		if reference, err := l.store(data, contentType); err == nil {
+			return reference
+		}
+		return v
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = l.offloadStrings(item, limit)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = l.offloadStrings(item, limit)
+		}
+	}
+	return value
+}
+
+func (l *limitEventManager) store(data []byte, contentType string) (string, error) {
+	reference, err := l.options.Storage.Store(context.Background(), data, contentType)
+	if err != nil {
+		log.Printf("error offloading value: %s", err)
+		return "", err
+	}
+	return fmt.Sprintf("[offloaded %s %d bytes: %s]", contentType, len(data), reference), nil
+}
+
+// truncate cuts the value to the limit, keeping runes whole, and marks how much was removed
+func truncate(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	end := limit
+	for end > 0 && !utf8.RuneStart(value[end]) {
+		end--
+	}
+	return fmt.Sprintf("%s...[truncated %d bytes]", value[:end], len(value)-end)
+}
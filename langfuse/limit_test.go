@@ -0,0 +1,75 @@
+package langfuse_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestLimitOptions(t *testing.T) {
+	t.Run("should truncate values over the limit", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		sdk = langfuse.New(context.TODO(), langfuse.Options{EventManager: sdk.EventManager(), Limits: &langfuse.LimitOptions{MaxInputSize: 100, MaxMetadataSize: 50}})
+		long := strings.Repeat("a", 1000)
+		_, _ = sdk.Generation(context.TODO(), &langfuse.Generation{BasicObservation: langfuse.BasicObservation{
+			TraceID:  "trace-id",
+			Input:    []map[string]interface{}{{"role": "system", "content": long}, {"role": "user", "content": "hi"}},
+			Output:   long,
+			Metadata: map[string]interface{}{"a": strings.Repeat("b", 30), "c": strings.Repeat("d", 30)},
+		}})
+		body := flush()[0]["body"].(map[string]interface{})
+		messages := body["input"].([]interface{})
+		content := messages[0].(map[string]interface{})["content"].(string)
+		if !regexp.MustCompile(`^a+\.\.\.\[truncated \d+ bytes\]$`).MatchString(content) {
+			t.Errorf("expected the long message to be truncated, got %s", content)
+		}
+		if input, _ := json.Marshal(messages); len(input) > 100 {
+			t.Errorf("expected input to be at most %d bytes, got %d", 100, len(input))
+		}
+		if messages[1].(map[string]interface{})["content"] != "hi" {
+			t.Errorf("expected short messages to be kept")
+		}
+		if body["output"] != long {
+			t.Errorf("expected output without a limit to be kept")
+		}
+		metadata, ok := body["metadata"].(string)
+		if !ok || !strings.HasSuffix(metadata, "bytes]") || !strings.HasPrefix(metadata, `{"a":"bbb`) {
+			t.Errorf("expected metadata to be truncated as json, got %v", body["metadata"])
+		}
+	})
+	t.Run("should offload values over the limit to storage", func(t *testing.T) {
+		dir := t.TempDir()
+		sdk, flush := captureBatch(t)
+		sdk = langfuse.New(context.TODO(), langfuse.Options{EventManager: sdk.EventManager(), Limits: &langfuse.LimitOptions{MaxInputSize: 400, Storage: langfuse.NewFileStorage(dir)}})
+		image := bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 100)
+		_, _ = sdk.Generation(context.TODO(), &langfuse.Generation{BasicObservation: langfuse.BasicObservation{
+			TraceID: "trace-id",
+			Input: []map[string]interface{}{{"role": "user", "content": []map[string]interface{}{
+				{"type": "text", "text": "what is this?"},
+				{"type": "image_url", "image_url": map[string]interface{}{"url": "data:image/png;base64," + base64.StdEncoding.EncodeToString(image)}},
+			}}},
+		}})
+		body := flush()[0]["body"].(map[string]interface{})
+		parts := body["input"].([]interface{})[0].(map[string]interface{})["content"].([]interface{})
+		if parts[0].(map[string]interface{})["text"] != "what is this?" {
+			t.Errorf("expected the text to be kept")
+		}
+		reference := parts[1].(map[string]interface{})["image_url"].(map[string]interface{})["url"].(string)
+		match := regexp.MustCompile(`^\[offloaded image/png 400 bytes: file://(.+)\]$`).FindStringSubmatch(reference)
+		if match == nil {
+			t.Fatalf("expected the image to be replaced with a reference, got %s", reference)
+		}
+		stored, err := os.ReadFile(filepath.FromSlash(match[1]))
+		if err != nil || !bytes.Equal(stored, image) {
+			t.Errorf("expected the image to be stored, got %v", err)
+		}
+	})
+}
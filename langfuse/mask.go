@@ -0,0 +1,280 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wepala/langfuse-go/api"
+)
+
+// Detector finds sensitive values in strings so they can be replaced with the detector's name
+type Detector struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// Valid filters the matches e.g. to check the checksum of credit card numbers. Every match is masked if it's nil
+	Valid func(match string) bool
+}
+
+var JWTDetector = &Detector{
+	Name:    "JWT",
+	Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+}
+
+var APIKeyDetector = &Detector{
+	Name:    "API_KEY",
+	Pattern: regexp.MustCompile(`\b(?:sk|pk)-[A-Za-z0-9_-]{16,}|\bAKIA[0-9A-Z]{16}\b|\bgh[pousr]_[A-Za-z0-9]{36,}\b|\bxox[abprs]-[A-Za-z0-9-]{10,}`),
+}
+
+var EmailDetector = &Detector{
+	Name:    "EMAIL",
+	Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+}
+
+var CreditCardDetector = &Detector{
+	Name:    "CREDIT_CARD",
+	Pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+	Valid:   luhn,
+}
+
+var PhoneDetector = &Detector{
+	Name:    "PHONE",
+	Pattern: regexp.MustCompile(`\+\d{1,3}[\s.-]?\(?\d{1,4}\)?(?:[\s.-]?\d{2,4}){2,4}\b|\(?\b\d{3}\)?[\s.-]\d{3}[\s.-]\d{4}\b`),
+}
+
+// DefaultDetectors are the built in detectors in the order they're applied
+var DefaultDetectors = []*Detector{JWTDetector, APIKeyDetector, EmailDetector, CreditCardDetector, PhoneDetector}
+
+func luhn(match string) bool {
+	sum := 0
+	double := false
+	digits := 0
+	for i := len(match) - 1; i >= 0; i-- {
+		if match[i] < '0' || match[i] > '9' {
+			continue
+		}
+		digit := int(match[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+// MaskingOptions configures how the input, output, metadata and status message of observations are masked before
+// they're sent
+type MaskingOptions struct {
+	// Detectors replace sensitive values in every string e.g. DefaultDetectors
+	Detectors []*Detector
+	// Paths are the fields that are always redacted e.g. $.input.password, $.metadata.user.email or
+	// $.output.messages[*].content. Paths start with the field of the observation they apply to, which includes
+	// $.statusMessage since error messages often contain the values that failed
+	Paths []string
+	// Replacement is used for fields that are redacted by path. Defaults to [REDACTED]
+	Replacement string
+	// UserIDs pseudonymizes the user ids of traces
+	UserIDs *Pseudonymizer
+	// Mask is called with the field (input, output, metadata or statusMessage) and its value after the other rules
+	// are applied
+	Mask func(field string, value interface{}) interface{}
+}
+
+// maskingEventManager masks events before passing them to the next event manager
+type maskingEventManager struct {
+	eventManager EventManager
+	options      MaskingOptions
+	paths        [][]string
+}
+
+func newMaskingEventManager(eventManager EventManager, options MaskingOptions) *maskingEventManager {
+	if options.Replacement == "" {
+		options.Replacement = "[REDACTED]"
+	}
+	var paths [][]string
+	for _, path := range options.Paths {
+		paths = append(paths, parsePath(path))
+	}
+	return &maskingEventManager{
+		eventManager: eventManager,
+		options:      options,
+		paths:        paths,
+	}
+}
+
+func (m *maskingEventManager) Enqueue(id string, eventType string, event interface{}) error {
+	ingestionEvent, ok, err := newIngestionEvent(id, eventType, event, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		//events the sdk doesn't know are masked as plain json
+		normalized, err := normalize(event)
+		if err != nil {
+			return err
+		}
+		body, ok := normalized.(map[string]interface{})
+		if !ok {
+			return m.eventManager.Enqueue(id, eventType, event)
+		}
+		for _, field := range []string{"input", "output", "metadata", "statusMessage"} {
+			if value, ok := body[field]; ok {
+				body[field] = m.mask(field, value)
+			}
+		}
+		return m.eventManager.Enqueue(id, eventType, body)
+	}
+	return m.eventManager.Enqueue(id, eventType, m.maskEvent(ingestionEvent))
+}
+
+func (m *maskingEventManager) Flush(ctxt context.Context) {
+	m.eventManager.Flush(ctxt)
+}
+
+func (m *maskingEventManager) Process(ctxt context.Context) {
+	if processor, ok := m.eventManager.(interface{ Process(ctxt context.Context) }); ok {
+		processor.Process(ctxt)
+	}
+}
+
+func (m *maskingEventManager) maskEvent(e *api.IngestionEvent) *api.IngestionEvent {
+	return transformEvent(e, func(fields *eventFields) {
+		fields.Input = m.maskField("input", fields.Input)
+		fields.Output = m.maskField("output", fields.Output)
+		fields.Metadata = m.maskField("metadata", fields.Metadata)
+		if fields.StatusMessage != nil {
+			statusMessage := fmt.Sprint(m.mask("statusMessage", *fields.StatusMessage))
+			fields.StatusMessage = &statusMessage
+		}
+		if m.options.UserIDs != nil && fields.UserID != nil {
+			userID := m.options.UserIDs.Pseudonymize(*fields.UserID)
+			fields.UserID = &userID
+		}
+	})
+}
+
+func (m *maskingEventManager) maskField(field string, value *interface{}) *interface{} {
+	if value == nil {
+		return nil
+	}
+	masked := m.mask(field, *value)
+	return &masked
+}
+
+func (m *maskingEventManager) mask(field string, value interface{}) interface{} {
+	//work on a json copy so values shared with the caller aren't changed and structs can be walked
+	value, err := normalize(value)
+	if err != nil {
+		return m.options.Replacement
+	}
+	for _, path := range m.paths {
+		if len(path) > 0 && path[0] == field {
+			value = redact(value, path[1:], m.options.Replacement)
+		}
+	}
+	value = m.detect(value)
+	if m.options.Mask != nil {
+		value = m.options.Mask(field, value)
+	}
+	return value
+}
+
+func (m *maskingEventManager) detect(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		for _, detector := range m.options.Detectors {
+			v = detector.Pattern.ReplaceAllStringFunc(v, func(match string) string {
+				if detector.Valid != nil && !detector.Valid(match) {
+					return match
+				}
+				return "[" + detector.Name + "]"
+			})
+		}
+		return v
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = m.detect(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = m.detect(item)
+		}
+	}
+	return value
+}
+
+func normalize(value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case nil, string, bool, float64, int:
+		return value, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+// parsePath splits a path like $.input.messages[*].content into input, messages, [*] and content
+func parsePath(path string) []string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		for part != "" {
+			i := strings.Index(part, "[")
+			if i < 0 {
+				segments = append(segments, part)
+				break
+			}
+			if i > 0 {
+				segments = append(segments, part[:i])
+			}
+			j := strings.Index(part, "]")
+			if j < i {
+				segments = append(segments, part[i:])
+				break
+			}
+			segments = append(segments, part[i:j+1])
+			part = part[j+1:]
+		}
+	}
+	return segments
+}
+
+// redact replaces the values at the path. Missing fields are ignored
+func redact(value interface{}, path []string, replacement string) interface{} {
+	if len(path) == 0 {
+		return replacement
+	}
+	segment, rest := path[0], path[1:]
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if segment == "*" || segment == "[*]" {
+			for key, item := range v {
+				v[key] = redact(item, rest, replacement)
+			}
+		} else if item, ok := v[segment]; ok {
+			v[segment] = redact(item, rest, replacement)
+		}
+	case []interface{}:
+		if segment == "*" || segment == "[*]" {
+			for i, item := range v {
+				v[i] = redact(item, rest, replacement)
+			}
+		} else if index, err := strconv.Atoi(strings.Trim(segment, "[]")); err == nil && index >= 0 && index < len(v) {
+			v[index] = redact(v[index], rest, replacement)
+		}
+	}
+	return value
+}
@@ -0,0 +1,81 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+// RecordedEvent is an event in the form it would have been sent to the ingestion api
+type RecordedEvent struct {
+	ID        string
+	Type      string
+	Timestamp time.Time
+	Body      map[string]interface{}
+}
+
+// MemoryEventManager keeps events in memory instead of sending them. Use it to run the sdk offline or to test
+// instrumentation
+type MemoryEventManager struct {
+	mu     sync.Mutex
+	events []RecordedEvent
+}
+
+func NewMemoryEventManager() *MemoryEventManager {
+	return &MemoryEventManager{}
+}
+
+// Enqueue records a snapshot of the event
+func (m *MemoryEventManager) Enqueue(id string, eventType string, event interface{}) error {
+	if id == "" {
+		id = ksuid.New().String()
+	}
+	now := time.Now()
+	ingestionEvent, ok, err := newIngestionEvent(id, eventType, event, now)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	if ok {
+		data, err = json.Marshal(ingestionEvent)
+	} else {
+		data, err = json.Marshal(map[string]interface{}{"id": id, "type": eventType, "body": event})
+	}
+	if err != nil {
+		return err
+	}
+	var snapshot struct {
+		ID   string                 `json:"id"`
+		Type string                 `json:"type"`
+		Body map[string]interface{} `json:"body"`
+	}
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, RecordedEvent{ID: snapshot.ID, Type: snapshot.Type, Timestamp: now, Body: snapshot.Body})
+	return nil
+}
+
+func (m *MemoryEventManager) Flush(ctxt context.Context) {}
+
+// Events returns the events that were recorded in the order they were enqueued
+func (m *MemoryEventManager) Events() []RecordedEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	events := make([]RecordedEvent, len(m.events))
+	copy(events, m.events)
+	return events
+}
+
+// Reset forgets the events that were recorded
+func (m *MemoryEventManager) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = nil
+}
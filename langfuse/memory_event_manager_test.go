@@ -0,0 +1,35 @@
+package langfuse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestMemoryEventManager_Enqueue(t *testing.T) {
+	t.Run("should record the events as they would be sent", func(t *testing.T) {
+		eventManager := langfuse.NewMemoryEventManager()
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager})
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{Name: "chat"}})
+		span, _ := trace.Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{Name: "retrieve"}})
+		span.SetOutput("first")
+		_ = span.End()
+		span.SetOutput("second")
+
+		events := eventManager.Events()
+		if len(events) != 3 {
+			t.Fatalf("expected %d events to be recorded, got %d", 3, len(events))
+		}
+		if events[0].Type != langfuse.TRACE_CREATE || events[1].Type != langfuse.SPAN_CREATE || events[2].Type != langfuse.SPAN_UPDATE {
+			t.Errorf("expected the event types to be recorded, got %s %s %s", events[0].Type, events[1].Type, events[2].Type)
+		}
+		if events[0].ID == "" || events[1].Body["traceId"] != trace.ID || events[2].Body["output"] != "first" {
+			t.Errorf("expected a snapshot of the ingestion event, got %v", events[2])
+		}
+		eventManager.Reset()
+		if len(eventManager.Events()) != 0 {
+			t.Errorf("expected the events to be forgotten")
+		}
+	})
+}
@@ -2,11 +2,17 @@ package langfuse
 
 import (
 	"errors"
+	"sync"
 	"time"
 
 	"github.com/segmentio/ksuid"
 )
 
+const LEVEL_DEBUG = "DEBUG"
+const LEVEL_DEFAULT = "DEFAULT"
+const LEVEL_WARNING = "WARNING"
+const LEVEL_ERROR = "ERROR"
+
 type Observation interface {
 	Span(opts *Span) (*Span, error)
 	Event(opts *Event) (*Event, error)
@@ -27,9 +33,91 @@ type BasicObservation struct {
 	Version       string                 `json:"version,omitempty"`
 	ParentID      string                 `json:"parentObservationId,omitempty"`
 	eventManager  EventManager
+	mu            *sync.RWMutex
+}
+
+// observationLock guards observations that weren't created through the sdk and so don't have their own lock
+var observationLock sync.RWMutex
+
+func (o *BasicObservation) init(eventManager EventManager) {
+	o.eventManager = eventManager
+	if o.mu == nil {
+		o.mu = &sync.RWMutex{}
+	}
+}
+
+func (o *BasicObservation) locker() *sync.RWMutex {
+	if o.mu == nil {
+		return &observationLock
+	}
+	return o.mu
+}
+
+// SetInput sets the input. It is safe to call while the observation is being used by other goroutines
+func (o *BasicObservation) SetInput(input interface{}) {
+	l := o.locker()
+	l.Lock()
+	defer l.Unlock()
+	o.Input = input
+}
+
+// SetOutput sets the output. It is safe to call while the observation is being used by other goroutines
+func (o *BasicObservation) SetOutput(output interface{}) {
+	l := o.locker()
+	l.Lock()
+	defer l.Unlock()
+	o.Output = output
 }
 
-func (o BasicObservation) Span(span *Span) (*Span, error) {
+// SetMetadata adds a metadata key. It is safe to call while the observation is being used by other goroutines
+func (o *BasicObservation) SetMetadata(key string, value interface{}) {
+	l := o.locker()
+	l.Lock()
+	defer l.Unlock()
+	//copy the map since snapshots taken when the observation was enqueued may still be referencing it
+	metadata := make(map[string]interface{}, len(o.Metadata)+1)
+	for k, v := range o.Metadata {
+		metadata[k] = v
+	}
+	metadata[key] = value
+	o.Metadata = metadata
+}
+
+// SetLevel sets the level. It is safe to call while the observation is being used by other goroutines
+func (o *BasicObservation) SetLevel(level string) {
+	l := o.locker()
+	l.Lock()
+	defer l.Unlock()
+	o.Level = level
+}
+
+// SetStatusMessage sets the status message. It is safe to call while the observation is being used by other goroutines
+func (o *BasicObservation) SetStatusMessage(message string) {
+	l := o.locker()
+	l.Lock()
+	defer l.Unlock()
+	o.StatusMessage = message
+}
+
+// setError marks the observation as failed
+func (o *BasicObservation) setError(err error) {
+	l := o.locker()
+	l.Lock()
+	defer l.Unlock()
+	o.Level = LEVEL_ERROR
+	if err != nil {
+		o.StatusMessage = err.Error()
+	}
+}
+
+func (o *BasicObservation) isError() bool {
+	l := o.locker()
+	l.RLock()
+	defer l.RUnlock()
+	return o.Level == LEVEL_ERROR
+}
+
+func (o *BasicObservation) Span(span *Span) (*Span, error) {
 	if span == nil {
 		span = &Span{}
 	}
@@ -51,13 +139,13 @@ func (o BasicObservation) Span(span *Span) (*Span, error) {
 		span.StartTime = time.Now()
 	}
 
-	span.eventManager = o.eventManager
+	span.init(o.eventManager)
 	err := o.eventManager.Enqueue("", SPAN_CREATE, span)
 
 	return span, err
 }
 
-func (o BasicObservation) Event(opts *Event) (*Event, error) {
+func (o *BasicObservation) Event(opts *Event) (*Event, error) {
 	if opts == nil {
 		opts = &Event{}
 	}
@@ -79,13 +167,13 @@ func (o BasicObservation) Event(opts *Event) (*Event, error) {
 		opts.StartTime = time.Now()
 	}
 
-	opts.eventManager = o.eventManager
-	o.eventManager.Enqueue("", EVENT_CREATE, opts)
+	opts.init(o.eventManager)
+	err := o.eventManager.Enqueue("", EVENT_CREATE, opts)
 
-	return opts, nil
+	return opts, err
 }
 
-func (o BasicObservation) Generation(generation *Generation) (*Generation, error) {
+func (o *BasicObservation) Generation(generation *Generation) (*Generation, error) {
 	if generation == nil {
 		generation = &Generation{}
 	}
@@ -107,13 +195,13 @@ func (o BasicObservation) Generation(generation *Generation) (*Generation, error
 		generation.StartTime = time.Now()
 	}
 
-	generation.eventManager = o.eventManager
+	generation.init(o.eventManager)
 	err := o.eventManager.Enqueue("", GENERATION_CREATE, generation)
 
 	return generation, err
 }
 
-func (o BasicObservation) Score(opts *Score) (*Score, error) {
+func (o *BasicObservation) Score(opts *Score) (*Score, error) {
 	if opts == nil {
 		opts = &Score{}
 	}
@@ -130,10 +218,27 @@ func (o BasicObservation) Score(opts *Score) (*Score, error) {
 		opts.Name = o.Name
 	}
 
-	opts.eventManager = o.eventManager
-	o.eventManager.Enqueue("", SCORE_CREATE, opts)
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts.init(o.eventManager)
+	err := o.eventManager.Enqueue("", SCORE_CREATE, opts)
 
-	return opts, nil
+	return opts, err
+}
+
+// observationScore scores the observation itself rather than its trace
+func (o *BasicObservation) observationScore(opts *Score) (*Score, error) {
+	if opts == nil {
+		opts = &Score{}
+	}
+
+	if opts.ObservationId == "" {
+		opts.ObservationId = o.ID
+	}
+
+	return o.Score(opts)
 }
 
 // // Update the observation with new values
@@ -153,9 +258,7 @@ func (s *Span) Update() error {
 		return errors.New("span id is not set")
 	}
 
-	s.eventManager.Enqueue("", SPAN_UPDATE, s)
-
-	return nil
+	return s.eventManager.Enqueue("", SPAN_UPDATE, s)
 }
 
 func (s *Span) End() error {
@@ -163,9 +266,35 @@ func (s *Span) End() error {
 		return errors.New("span id is not set")
 	}
 	now := time.Now()
+	l := s.locker()
+	l.Lock()
 	s.EndTime = &now
-	s.eventManager.Enqueue("", SPAN_UPDATE, s)
-	return nil
+	l.Unlock()
+	return s.eventManager.Enqueue("", SPAN_UPDATE, s)
+}
+
+// EndWithOutput sets the output and ends the span
+func (s *Span) EndWithOutput(output interface{}) error {
+	s.SetOutput(output)
+	return s.End()
+}
+
+// EndWithError sets the level to ERROR with the error as the status message and ends the span
+func (s *Span) EndWithError(err error) error {
+	s.setError(err)
+	return s.End()
+}
+
+// Finish ends the span with the error err points to, if any. It's meant to be deferred in functions with a named
+// error result e.g. defer span.Finish(&err). If ending the span fails and there is no other error, err is set to
+// that error
+func (s *Span) Finish(err *error) {
+	finish(err, s.End, s.EndWithError)
+}
+
+// Score creates a score for the span
+func (s *Span) Score(opts *Score) (*Score, error) {
+	return s.BasicObservation.observationScore(opts)
 }
 
 type Generation struct {
@@ -185,8 +314,7 @@ func (g *Generation) Update() error {
 		return errors.New("generation id is not set")
 	}
 
-	g.eventManager.Enqueue("", GENERATION_UPDATE, g)
-	return nil
+	return g.eventManager.Enqueue("", GENERATION_UPDATE, g)
 }
 
 func (g *Generation) End() error {
@@ -194,19 +322,98 @@ func (g *Generation) End() error {
 		return errors.New("span id is not set")
 	}
 	now := time.Now()
+	l := g.locker()
+	l.Lock()
 	g.EndTime = &now
+	l.Unlock()
 	err := g.eventManager.Enqueue("", GENERATION_UPDATE, g)
 	return err
 }
 
+// SetModel sets the model. It is safe to call while the generation is being used by other goroutines
+func (g *Generation) SetModel(model string) {
+	l := g.locker()
+	l.Lock()
+	defer l.Unlock()
+	g.Model = model
+}
+
+// SetUsage sets the usage. It is safe to call while the generation is being used by other goroutines
+func (g *Generation) SetUsage(usage map[string]interface{}) {
+	l := g.locker()
+	l.Lock()
+	defer l.Unlock()
+	g.Usage = usage
+}
+
+// SetCompletionStartTime sets when the model started responding. It is safe to call while the generation is being
+// used by other goroutines
+func (g *Generation) SetCompletionStartTime(completionStartTime time.Time) {
+	l := g.locker()
+	l.Lock()
+	defer l.Unlock()
+	g.CompletionStartTime = completionStartTime
+}
+
+// EndWithOutput sets the output and ends the generation
+func (g *Generation) EndWithOutput(output interface{}) error {
+	g.SetOutput(output)
+	return g.End()
+}
+
+// EndWithError sets the level to ERROR with the error as the status message and ends the generation
+func (g *Generation) EndWithError(err error) error {
+	g.setError(err)
+	return g.End()
+}
+
+// Finish ends the generation with the error err points to, if any. See Span.Finish
+func (g *Generation) Finish(err *error) {
+	finish(err, g.End, g.EndWithError)
+}
+
+// Score creates a score for the generation
+func (g *Generation) Score(opts *Score) (*Score, error) {
+	return g.BasicObservation.observationScore(opts)
+}
+
 type Event struct {
 	BasicObservation
-	StartTime time.Time `json:"start_time"`
+	StartTime time.Time `json:"startTime,omitempty"`
 }
 
-type Score struct {
-	BasicObservation
-	Value         int    `json:"value,omitempty,default:0"`
-	ObservationId string `json:"observationId,omitempty"`
-	Comment       string `json:"comment,omitempty"`
+// Update sends the current values of the event. Events don't have a duration so there is no End
+func (e *Event) Update() error {
+	if e.ID == "" {
+		return errors.New("event id is not set")
+	}
+
+	return e.eventManager.Enqueue("", EVENT_UPDATE, e)
+}
+
+// UpdateWithError sets the level to ERROR with the error as the status message and updates the event
+func (e *Event) UpdateWithError(err error) error {
+	e.setError(err)
+	return e.Update()
+}
+
+// Score creates a score for the event
+func (e *Event) Score(opts *Score) (*Score, error) {
+	return e.BasicObservation.observationScore(opts)
+}
+
+func finish(err *error, end func() error, endWithError func(error) error) {
+	var cause error
+	if err != nil {
+		cause = *err
+	}
+	var endErr error
+	if cause != nil {
+		endErr = endWithError(cause)
+	} else {
+		endErr = end()
+	}
+	if endErr != nil && err != nil && *err == nil {
+		*err = endErr
+	}
 }
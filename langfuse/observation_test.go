@@ -1 +1,204 @@
 package langfuse_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestBasicObservation_Setters(t *testing.T) {
+	t.Run("should be safe to update a span from multiple goroutines", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{})
+		span, err := trace.Span(&langfuse.Span{})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				span.SetOutput(fmt.Sprintf("output %d", i))
+				span.SetMetadata(fmt.Sprintf("key%d", i), i)
+				span.SetLevel("DEBUG")
+				span.SetStatusMessage("running")
+				if err := span.Update(); err != nil {
+					t.Errorf("expected no error, got %s", err)
+				}
+			}(i)
+		}
+		wg.Wait()
+		if err = span.End(); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		events := flush()
+		if len(events) != 13 {
+			t.Fatalf("expected %d events to be sent, got %d", 13, len(events))
+		}
+		metadata := events[12]["body"].(map[string]interface{})["metadata"].(map[string]interface{})
+		if len(metadata) != 10 {
+			t.Errorf("expected all metadata keys to be set, got %v", metadata)
+		}
+	})
+	t.Run("should not change an event that was already enqueued", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{})
+		span, _ := trace.Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{Output: "first"}})
+		span.SetOutput("second")
+		span.SetMetadata("key", "value")
+		events := flush()
+		body := events[1]["body"].(map[string]interface{})
+		if body["output"] != "first" {
+			t.Errorf("expected output to be the value at enqueue time, got %v", body["output"])
+		}
+		if body["metadata"] != nil {
+			t.Errorf("expected metadata not to be set, got %v", body["metadata"])
+		}
+	})
+	t.Run("should be safe to create children while the parent is being updated", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{})
+		span, _ := trace.Span(&langfuse.Span{})
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(2)
+			go func(i int) {
+				defer wg.Done()
+				span.SetOutput(fmt.Sprintf("output %d", i))
+				trace.SetMetadata("key", i)
+			}(i)
+			go func() {
+				defer wg.Done()
+				if _, err := span.Event(&langfuse.Event{}); err != nil {
+					t.Errorf("expected no error, got %s", err)
+				}
+				if _, err := trace.Span(&langfuse.Span{}); err != nil {
+					t.Errorf("expected no error, got %s", err)
+				}
+			}()
+		}
+		wg.Wait()
+		if events := flush(); len(events) != 12 {
+			t.Errorf("expected %d events to be sent, got %d", 12, len(events))
+		}
+	})
+	t.Run("should not send changes made to nested metadata after the event was enqueued", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		user := map[string]interface{}{"plan": "free"}
+		_, _ = sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{Metadata: map[string]interface{}{"user": user}}})
+		user["plan"] = "pro"
+		events := flush()
+		metadata := events[0]["body"].(map[string]interface{})["metadata"].(map[string]interface{})
+		if plan := metadata["user"].(map[string]interface{})["plan"]; plan != "free" {
+			t.Errorf("expected the metadata at enqueue time to be sent, got %v", plan)
+		}
+	})
+	t.Run("should not send changes made to the input and output after the event was enqueued", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{})
+		input := map[string]interface{}{"query": "first"}
+		output := map[string]string{"answer": "first"}
+		_, _ = trace.Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{Input: input, Output: output}})
+		input["query"] = "second"
+		output["answer"] = "second"
+		body := flush()[1]["body"].(map[string]interface{})
+		if query := body["input"].(map[string]interface{})["query"]; query != "first" {
+			t.Errorf("expected the input at enqueue time to be sent, got %v", query)
+		}
+		if answer := body["output"].(map[string]interface{})["answer"]; answer != "first" {
+			t.Errorf("expected the output at enqueue time to be sent, got %v", answer)
+		}
+	})
+	t.Run("should allow setters on observations created without the sdk", func(t *testing.T) {
+		span := &langfuse.Span{}
+		span.SetMetadata("key", "value")
+		if span.Metadata["key"] != "value" {
+			t.Errorf("expected metadata to be set")
+		}
+	})
+}
+
+func TestSpan_Lifecycle(t *testing.T) {
+	t.Run("should end the span with the output", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		span, _ := sdk.Span(context.TODO(), &langfuse.Span{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id"}})
+		if err := span.EndWithOutput("done"); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		body := flush()[1]["body"].(map[string]interface{})
+		if body["output"] != "done" || body["endTime"] == nil {
+			t.Errorf("expected span to be ended with output, got %v", body)
+		}
+	})
+	t.Run("should end the span with the error", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		span, _ := sdk.Span(context.TODO(), &langfuse.Span{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id"}})
+		if err := span.EndWithError(errors.New("timeout")); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		body := flush()[1]["body"].(map[string]interface{})
+		if body["level"] != langfuse.LEVEL_ERROR {
+			t.Errorf("expected level to be %s, got %v", langfuse.LEVEL_ERROR, body["level"])
+		}
+		if body["statusMessage"] != "timeout" {
+			t.Errorf("expected statusMessage to be %s, got %v", "timeout", body["statusMessage"])
+		}
+	})
+	t.Run("should finish the span with the returned error", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		run := func() (err error) {
+			span, _ := sdk.Span(context.TODO(), &langfuse.Span{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id"}})
+			defer span.Finish(&err)
+			return errors.New("failed")
+		}
+		if err := run(); err == nil || err.Error() != "failed" {
+			t.Errorf("expected the original error to be returned, got %v", err)
+		}
+		body := flush()[1]["body"].(map[string]interface{})
+		if body["level"] != langfuse.LEVEL_ERROR || body["endTime"] == nil {
+			t.Errorf("expected span to be ended with an error, got %v", body)
+		}
+	})
+	t.Run("should return the error from ending the span if there is no other error", func(t *testing.T) {
+		eventManager := &EventManagerMock{
+			EnqueueFunc: func(id string, eventType string, event interface{}) error {
+				if eventType == langfuse.GENERATION_UPDATE {
+					return errors.New("queue full")
+				}
+				return nil
+			},
+		}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager})
+		run := func() (err error) {
+			generation, _ := sdk.Generation(context.TODO(), &langfuse.Generation{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id"}})
+			defer generation.Finish(&err)
+			return nil
+		}
+		if err := run(); err == nil {
+			t.Errorf("expected an error to be returned")
+		}
+	})
+}
+
+func TestEvent_Update(t *testing.T) {
+	t.Run("should send event updates as observation updates", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		event, _ := sdk.Event(context.TODO(), &langfuse.Event{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id"}})
+		if err := event.UpdateWithError(errors.New("rejected")); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		events := flush()
+		if events[1]["type"] != langfuse.OBSERVATION_UPDATE {
+			t.Fatalf("expected event type to be %s, got %v", langfuse.OBSERVATION_UPDATE, events[1]["type"])
+		}
+		body := events[1]["body"].(map[string]interface{})
+		if body["id"] != event.ID || body["type"] != "EVENT" || body["statusMessage"] != "rejected" {
+			t.Errorf("expected the event to be updated, got %v", body)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package langfuse
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const pseudonymPrefix = "pii_"
+
+// Pseudonymizer replaces values with tokens that can only be turned back into the original value with the key. The
+// same value always gets the same token so traces can still be grouped by it
+type Pseudonymizer struct {
+	macKey []byte
+	aead   cipher.AEAD
+}
+
+// NewPseudonymizer creates a pseudonymizer with a 16, 24 or 32 byte key
+func NewPseudonymizer(key []byte) (*Pseudonymizer, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, fmt.Errorf("invalid key: %w", aes.KeySizeError(len(key)))
+	}
+	//the nonces and the encryption use separate keys derived from the key so neither is used for two purposes
+	block, err := aes.NewCipher(deriveKey(key, "enc")[:len(key)])
+	if err != nil {
+		return nil, fmt.Errorf("invalid key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Pseudonymizer{macKey: deriveKey(key, "mac"), aead: aead}, nil
+}
+
+// deriveKey derives a key for the purpose from the key
+func deriveKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// Pseudonymize returns the token for the value
+func (p *Pseudonymizer) Pseudonymize(value string) string {
+	//the nonce is derived from the value so the token is deterministic
+	mac := hmac.New(sha256.New, p.macKey)
+	mac.Write([]byte(value))
+	nonce := mac.Sum(nil)[:p.aead.NonceSize()]
+	sealed := p.aead.Seal(nonce, nonce, []byte(value), nil)
+	return pseudonymPrefix + base64.RawURLEncoding.EncodeToString(sealed)
+}
+
+// Reveal returns the value the token was created from
+func (p *Pseudonymizer) Reveal(token string) (string, error) {
+	if !strings.HasPrefix(token, pseudonymPrefix) {
+		return "", errors.New("not a pseudonym")
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, pseudonymPrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid pseudonym: %w", err)
+	}
+	if len(sealed) < p.aead.NonceSize() {
+		return "", errors.New("invalid pseudonym")
+	}
+	value, err := p.aead.Open(nil, sealed[:p.aead.NonceSize()], sealed[p.aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid pseudonym: %w", err)
+	}
+	return string(value), nil
+}
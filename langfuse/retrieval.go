@@ -0,0 +1,98 @@
+package langfuse
+
+// Metadata keys used to describe embedding, retrieval and rerank observations
+const METADATA_INPUT_COUNT = "inputCount"
+const METADATA_DIMENSIONS = "dimensions"
+const METADATA_TOP_K = "topK"
+const METADATA_DOCUMENT_IDS = "documentIds"
+const METADATA_SCORES = "scores"
+
+const OBSERVATION_EMBEDDING = "embedding"
+const OBSERVATION_RETRIEVAL = "retrieval"
+const OBSERVATION_RERANK = "rerank"
+
+// Embedding is a generation for an embedding call so the model and tokens are counted in usage. The inputs are
+// recorded as the input of the generation but the vectors are not recorded
+type Embedding struct {
+	Generation
+	Inputs []string
+}
+
+// Document is a document returned by a retrieval or rerank step
+type Document struct {
+	ID       string                 `json:"id,omitempty"`
+	Score    float64                `json:"score"`
+	Content  string                 `json:"content,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Retrieval is a span for a vector search or rerank. The query is the input and the documents are the output
+type Retrieval struct {
+	Span
+	Query string
+	TopK  int
+}
+
+func (o *BasicObservation) Embedding(opts *Embedding) (*Embedding, error) {
+	if opts == nil {
+		opts = &Embedding{}
+	}
+
+	if opts.Inputs != nil && opts.Input == nil {
+		opts.Input = opts.Inputs
+	}
+	opts.Metadata = withMetadata(opts.Metadata, map[string]interface{}{
+		METADATA_TYPE:        OBSERVATION_EMBEDDING,
+		METADATA_INPUT_COUNT: len(opts.Inputs),
+	})
+
+	_, err := o.Generation(&opts.Generation)
+	return opts, err
+}
+
+// EndWithResult records the size of the vectors and the tokens used as the usage and ends the generation
+func (e *Embedding) EndWithResult(dimensions int, tokens int) error {
+	e.SetMetadata(METADATA_DIMENSIONS, dimensions)
+	e.SetUsage(map[string]interface{}{"input": tokens, "total": tokens, "unit": "TOKENS"})
+	return e.End()
+}
+
+func (o *BasicObservation) Retrieval(opts *Retrieval) (*Retrieval, error) {
+	return o.retrieval(opts, OBSERVATION_RETRIEVAL)
+}
+
+// Rerank records a rerank step. The documents passed to EndWithDocuments are the reranked documents
+func (o *BasicObservation) Rerank(opts *Retrieval) (*Retrieval, error) {
+	return o.retrieval(opts, OBSERVATION_RERANK)
+}
+
+func (o *BasicObservation) retrieval(opts *Retrieval, observationType string) (*Retrieval, error) {
+	if opts == nil {
+		opts = &Retrieval{}
+	}
+
+	if opts.Query != "" && opts.Input == nil {
+		opts.Input = opts.Query
+	}
+	values := map[string]interface{}{METADATA_TYPE: observationType}
+	if opts.TopK > 0 {
+		values[METADATA_TOP_K] = opts.TopK
+	}
+	opts.Metadata = withMetadata(opts.Metadata, values)
+
+	_, err := o.Span(&opts.Span)
+	return opts, err
+}
+
+// EndWithDocuments records the documents as the output, their ids and scores in order as metadata and ends the span
+func (r *Retrieval) EndWithDocuments(documents []Document) error {
+	ids := make([]string, len(documents))
+	scores := make([]float64, len(documents))
+	for i, document := range documents {
+		ids[i] = document.ID
+		scores[i] = document.Score
+	}
+	r.SetMetadata(METADATA_DOCUMENT_IDS, ids)
+	r.SetMetadata(METADATA_SCORES, scores)
+	return r.EndWithOutput(documents)
+}
@@ -0,0 +1,65 @@
+package langfuse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestBasicObservation_Retrieval(t *testing.T) {
+	t.Run("should record the embedding, retrieval and rerank steps", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
+		embedding, err := trace.Embedding(&langfuse.Embedding{Generation: langfuse.Generation{Model: "text-embedding-3-small"}, Inputs: []string{"what is langfuse?"}})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		_ = embedding.EndWithResult(1536, 5)
+		retrieval, err := trace.Retrieval(&langfuse.Retrieval{Query: "what is langfuse?", TopK: 2})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		_ = retrieval.EndWithDocuments([]langfuse.Document{{ID: "doc-1", Score: 0.8}, {ID: "doc-2", Score: 0.7}})
+		rerank, _ := trace.Rerank(&langfuse.Retrieval{Query: "what is langfuse?"})
+		_ = rerank.EndWithDocuments([]langfuse.Document{{ID: "doc-2", Score: 0.9}})
+		events := flush()
+		if len(events) != 7 {
+			t.Fatalf("expected %d events to be sent, got %d", 7, len(events))
+		}
+
+		if events[0]["type"] != langfuse.TRACE_CREATE || events[1]["type"] != langfuse.GENERATION_CREATE || events[2]["type"] != langfuse.GENERATION_UPDATE {
+			t.Fatalf("expected the embedding to be recorded as a generation, got %v and %v", events[1]["type"], events[2]["type"])
+		}
+		body := events[2]["body"].(map[string]interface{})
+		metadata := body["metadata"].(map[string]interface{})
+		if metadata[langfuse.METADATA_TYPE] != langfuse.OBSERVATION_EMBEDDING || body["model"] != "text-embedding-3-small" {
+			t.Errorf("expected embedding metadata, got %v", metadata)
+		}
+		if metadata[langfuse.METADATA_INPUT_COUNT] != float64(1) || metadata[langfuse.METADATA_DIMENSIONS] != float64(1536) {
+			t.Errorf("expected the embedding size to be recorded, got %v", metadata)
+		}
+		if usage := body["usage"].(map[string]interface{}); usage["input"] != float64(5) || usage["total"] != float64(5) || usage["unit"] != "TOKENS" {
+			t.Errorf("expected the tokens to be recorded as usage, got %v", usage)
+		}
+
+		body = events[4]["body"].(map[string]interface{})
+		metadata = body["metadata"].(map[string]interface{})
+		if metadata[langfuse.METADATA_TYPE] != langfuse.OBSERVATION_RETRIEVAL || metadata[langfuse.METADATA_TOP_K] != float64(2) {
+			t.Errorf("expected retrieval metadata, got %v", metadata)
+		}
+		ids := metadata[langfuse.METADATA_DOCUMENT_IDS].([]interface{})
+		scores := metadata[langfuse.METADATA_SCORES].([]interface{})
+		if len(ids) != 2 || ids[0] != "doc-1" || scores[1] != 0.7 {
+			t.Errorf("expected document ids and scores in order, got %v %v", ids, scores)
+		}
+		if len(body["output"].([]interface{})) != 2 {
+			t.Errorf("expected the documents to be the output, got %v", body["output"])
+		}
+
+		metadata = events[6]["body"].(map[string]interface{})["metadata"].(map[string]interface{})
+		if metadata[langfuse.METADATA_TYPE] != langfuse.OBSERVATION_RERANK || metadata[langfuse.METADATA_TOP_K] != nil {
+			t.Errorf("expected rerank metadata, got %v", metadata)
+		}
+	})
+}
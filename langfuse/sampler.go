@@ -0,0 +1,220 @@
+package langfuse
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+)
+
+// Sampler decides whether a trace is sent. Traces that aren't sampled are still returned by LangFuse.Trace but the
+// trace and everything created from it is dropped
+type Sampler interface {
+	Sample(trace *Trace) bool
+}
+
+// SamplerFunc adapts a function to a Sampler
+type SamplerFunc func(trace *Trace) bool
+
+func (f SamplerFunc) Sample(trace *Trace) bool {
+	return f(trace)
+}
+
+// RatioSampler samples the given fraction of traces. The decision is based on the trace id so every service that
+// sees the same trace id makes the same decision
+func RatioSampler(ratio float64) Sampler {
+	return SamplerFunc(func(trace *Trace) bool {
+		if ratio >= 1 {
+			return true
+		}
+		if ratio <= 0 {
+			return false
+		}
+		hash := fnv.New64a()
+		_, _ = hash.Write([]byte(trace.ID))
+		//fnv doesn't spread similar ids across the high bits so mix them before comparing
+		h := hash.Sum64()
+		h ^= h >> 33
+		h *= 0xff51afd7ed558ccd
+		h ^= h >> 33
+		h *= 0xc4ceb9fe1a85ec53
+		h ^= h >> 33
+		return float64(h) < ratio*math.MaxUint64
+	})
+}
+
+// RateLimitSampler samples at most perSecond traces a second
+func RateLimitSampler(perSecond int) Sampler {
+	return &rateLimitSampler{
+		perSecond: float64(perSecond),
+		tokens:    float64(perSecond),
+		last:      time.Now(),
+	}
+}
+
+type rateLimitSampler struct {
+	mu        sync.Mutex
+	perSecond float64
+	tokens    float64
+	last      time.Time
+}
+
+func (r *rateLimitSampler) Sample(trace *Trace) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.tokens = math.Min(r.perSecond, r.tokens+now.Sub(r.last).Seconds()*r.perSecond)
+	r.last = now
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// SamplingRule applies a sampler to the traces that match it. Empty fields match every trace
+type SamplingRule struct {
+	Name    string
+	UserID  string
+	Tag     string
+	Sampler Sampler
+}
+
+func (r SamplingRule) matches(trace *Trace) bool {
+	if r.Name != "" && r.Name != trace.Name {
+		return false
+	}
+	if r.UserID != "" && r.UserID != trace.UserID {
+		return false
+	}
+	if r.Tag != "" {
+		for _, tag := range trace.Tags {
+			if tag == r.Tag {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// RuleSampler uses the sampler of the first rule that matches the trace. Traces that don't match a rule are sampled
+// with the default sampler, or always sampled if there is no default
+type RuleSampler struct {
+	Rules   []SamplingRule
+	Default Sampler
+}
+
+func (r *RuleSampler) Sample(trace *Trace) bool {
+	for _, rule := range r.Rules {
+		if rule.matches(trace) {
+			return rule.Sampler == nil || rule.Sampler.Sample(trace)
+		}
+	}
+	return r.Default == nil || r.Default.Sample(trace)
+}
+
+// MAX_UNSAMPLED_EVENTS is how many events of an unsampled trace are held in case one of its observations fails.
+// Traces with more events are dropped even if they fail later
+const MAX_UNSAMPLED_EVENTS = 1000
+
+// MAX_SAMPLING_DECISIONS is how many traces' sampling decisions are kept so that spans, generations, events and scores
+// created on the sdk with the id of a trace get the same decision as the trace. Once there are more traces the oldest
+// decisions are dropped and those traces are sampled again
+const MAX_SAMPLING_DECISIONS = 10000
+
+// samplingDecisions holds the event manager chosen for each recent trace
+type samplingDecisions struct {
+	mu       sync.Mutex
+	managers map[string]EventManager
+	order    []string
+	next     int
+}
+
+func newSamplingDecisions() *samplingDecisions {
+	return &samplingDecisions{managers: make(map[string]EventManager)}
+}
+
+// get returns the event manager chosen for the trace id, calling decide if there isn't one yet
+func (d *samplingDecisions) get(traceID string, decide func() EventManager) EventManager {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if eventManager, ok := d.managers[traceID]; ok {
+		return eventManager
+	}
+	eventManager := decide()
+	if len(d.order) < MAX_SAMPLING_DECISIONS {
+		d.order = append(d.order, traceID)
+	} else {
+		delete(d.managers, d.order[d.next])
+		d.order[d.next] = traceID
+		d.next = (d.next + 1) % MAX_SAMPLING_DECISIONS
+	}
+	d.managers[traceID] = eventManager
+	return eventManager
+}
+
+type bufferedEvent struct {
+	id        string
+	eventType string
+	event     interface{}
+}
+
+// unsampledEventManager drops the events of a trace that wasn't sampled. If errors are always sampled the events are
+// held until an observation with the ERROR level is enqueued, at which point the whole trace is sent
+type unsampledEventManager struct {
+	eventManager EventManager
+	sampleErrors bool
+	mu           sync.Mutex
+	sampled      bool
+	dropped      bool
+	events       []bufferedEvent
+}
+
+func (u *unsampledEventManager) Enqueue(id string, eventType string, event interface{}) error {
+	if !u.sampleErrors {
+		return nil
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.sampled {
+		return u.eventManager.Enqueue(id, eventType, event)
+	}
+	if u.dropped {
+		return nil
+	}
+
+	observation, ok := event.(interface{ isError() bool })
+	failed := ok && observation.isError()
+	//snapshot the event since it may change before the trace is sent
+	if ingestionEvent, ok, err := newIngestionEvent(id, eventType, event, time.Now()); ok {
+		if err != nil {
+			return err
+		}
+		event = ingestionEvent
+	}
+	u.events = append(u.events, bufferedEvent{id: id, eventType: eventType, event: event})
+	if !failed {
+		//stop holding the events of long running traces rather than sending part of the trace
+		if len(u.events) >= MAX_UNSAMPLED_EVENTS {
+			u.dropped = true
+			u.events = nil
+		}
+		return nil
+	}
+
+	u.sampled = true
+	for _, buffered := range u.events {
+		if err := u.eventManager.Enqueue(buffered.id, buffered.eventType, buffered.event); err != nil {
+			return err
+		}
+	}
+	u.events = nil
+	return nil
+}
+
+func (u *unsampledEventManager) Flush(ctxt context.Context) {
+	u.eventManager.Flush(ctxt)
+}
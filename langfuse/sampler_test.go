@@ -0,0 +1,203 @@
+package langfuse_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestRatioSampler(t *testing.T) {
+	t.Run("should make the same decision for the same trace id", func(t *testing.T) {
+		sampler := langfuse.RatioSampler(0.5)
+		trace := &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}}
+		first := sampler.Sample(trace)
+		for i := 0; i < 10; i++ {
+			if sampler.Sample(trace) != first {
+				t.Fatalf("expected the decision to be deterministic")
+			}
+		}
+	})
+	t.Run("should sample roughly the ratio of traces", func(t *testing.T) {
+		sampler := langfuse.RatioSampler(0.25)
+		sampled := 0
+		for i := 0; i < 10000; i++ {
+			if sampler.Sample(&langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: fmt.Sprintf("trace-%d", i)}}) {
+				sampled++
+			}
+		}
+		if sampled < 2000 || sampled > 3000 {
+			t.Errorf("expected about %d traces to be sampled, got %d", 2500, sampled)
+		}
+	})
+}
+
+func TestRateLimitSampler(t *testing.T) {
+	t.Run("should not sample more than the limit", func(t *testing.T) {
+		sampler := langfuse.RateLimitSampler(5)
+		sampled := 0
+		for i := 0; i < 20; i++ {
+			if sampler.Sample(&langfuse.Trace{}) {
+				sampled++
+			}
+		}
+		if sampled != 5 {
+			t.Errorf("expected %d traces to be sampled, got %d", 5, sampled)
+		}
+	})
+}
+
+func TestRuleSampler(t *testing.T) {
+	sampler := &langfuse.RuleSampler{
+		Rules: []langfuse.SamplingRule{
+			{Name: "health-check", Sampler: langfuse.RatioSampler(0)},
+			{Tag: "debug", Sampler: langfuse.RatioSampler(1)},
+			{UserID: "internal", Sampler: langfuse.RatioSampler(0)},
+		},
+		Default: langfuse.RatioSampler(0),
+	}
+	tests := []struct {
+		name     string
+		trace    *langfuse.Trace
+		expected bool
+	}{
+		{"should use the rule matching the name", &langfuse.Trace{BasicObservation: langfuse.BasicObservation{Name: "health-check"}, Tags: []string{"debug"}}, false},
+		{"should use the rule matching a tag", &langfuse.Trace{UserID: "internal", Tags: []string{"debug"}}, true},
+		{"should use the rule matching the user", &langfuse.Trace{UserID: "internal"}, false},
+		{"should use the default if no rule matches", &langfuse.Trace{}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if sampler.Sample(test.trace) != test.expected {
+				t.Errorf("expected sampled to be %t", test.expected)
+			}
+		})
+	}
+}
+
+func TestLangFuse_Trace_Sampling(t *testing.T) {
+	t.Run("should drop unsampled traces and their observations", func(t *testing.T) {
+		eventManager := &EventManagerMock{}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager, Sampler: langfuse.RatioSampler(0)})
+		trace, err := sdk.Trace(context.TODO(), nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		span, err := trace.Span(nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		_ = span.EndWithError(errors.New("failed"))
+		if len(eventManager.calls.Enqueue) != 0 {
+			t.Errorf("expected no events to be enqueued, got %d", len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should send unsampled traces with errors if errors are always sampled", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		sdk = langfuse.New(context.TODO(), langfuse.Options{EventManager: sdk.EventManager(), Sampler: langfuse.RatioSampler(0), SampleErrors: true})
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{Name: "first"}})
+		span, _ := trace.Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{Name: "before"}})
+		span.Name = "changed"
+		_ = span.EndWithError(errors.New("failed"))
+		_, _ = trace.Event(nil)
+		healthy, _ := sdk.Trace(context.TODO(), nil)
+		_, _ = healthy.Span(nil)
+		events := flush()
+		types := []string{langfuse.TRACE_CREATE, langfuse.SPAN_CREATE, langfuse.SPAN_UPDATE, langfuse.EVENT_CREATE}
+		if len(events) != len(types) {
+			t.Fatalf("expected %d events to be sent, got %d", len(types), len(events))
+		}
+		for i, eventType := range types {
+			if events[i]["type"] != eventType {
+				t.Errorf("expected event %d to be %s, got %v", i, eventType, events[i]["type"])
+			}
+		}
+		if name := events[1]["body"].(map[string]interface{})["name"]; name != "before" {
+			t.Errorf("expected buffered events to be a snapshot, got %v", name)
+		}
+	})
+	t.Run("should drop the events of unsampled traces with more events than are held", func(t *testing.T) {
+		eventManager := &EventManagerMock{EnqueueFunc: func(id string, eventType string, event interface{}) error { return nil }}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager, Sampler: langfuse.RatioSampler(0), SampleErrors: true})
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		for i := 0; i < langfuse.MAX_UNSAMPLED_EVENTS; i++ {
+			_, _ = trace.Event(nil)
+		}
+		span, _ := trace.Span(nil)
+		_ = span.EndWithError(errors.New("failed"))
+		if len(eventManager.calls.Enqueue) != 0 {
+			t.Errorf("expected no events to be enqueued, got %d", len(eventManager.calls.Enqueue))
+		}
+	})
+}
+
+func TestLangFuse_Observation_Sampling(t *testing.T) {
+	t.Run("should make one sampling decision for each trace id", func(t *testing.T) {
+		var sampled []string
+		sampler := langfuse.SamplerFunc(func(trace *langfuse.Trace) bool {
+			sampled = append(sampled, trace.ID)
+			return trace.ID == "sampled"
+		})
+		eventManager := &EventManagerMock{EnqueueFunc: func(id string, eventType string, event interface{}) error { return nil }}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager, Sampler: sampler})
+		for _, traceID := range []string{"sampled", "unsampled"} {
+			observation := langfuse.BasicObservation{TraceID: traceID}
+			_, _ = sdk.Span(context.TODO(), &langfuse.Span{BasicObservation: observation})
+			_, _ = sdk.Generation(context.TODO(), &langfuse.Generation{BasicObservation: observation})
+			_, _ = sdk.Event(context.TODO(), &langfuse.Event{BasicObservation: observation})
+		}
+		if len(sampled) != 2 {
+			t.Errorf("expected the sampler to be called once for each trace, got %v", sampled)
+		}
+		if len(eventManager.calls.Enqueue) != 3 {
+			t.Fatalf("expected only the observations of the sampled trace to be enqueued, got %d", len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should use the decision made for the trace", func(t *testing.T) {
+		eventManager := &EventManagerMock{EnqueueFunc: func(id string, eventType string, event interface{}) error { return nil }}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager, Sampler: &langfuse.RuleSampler{
+			Rules:   []langfuse.SamplingRule{{Name: "checkout", Sampler: langfuse.RateLimitSampler(1)}},
+			Default: langfuse.RatioSampler(0),
+		}})
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{Name: "checkout"}})
+		for i := 0; i < 3; i++ {
+			if _, err := sdk.Span(context.TODO(), &langfuse.Span{BasicObservation: langfuse.BasicObservation{TraceID: trace.ID}}); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+		}
+		if len(eventManager.calls.Enqueue) != 4 {
+			t.Errorf("expected the trace and its spans to be enqueued, got %d", len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should not send scores of unsampled traces", func(t *testing.T) {
+		eventManager := &EventManagerMock{EnqueueFunc: func(id string, eventType string, event interface{}) error { return nil }}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager, Sampler: langfuse.RatioSampler(0)})
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		if _, err := sdk.Score(context.TODO(), &langfuse.Score{BasicObservation: langfuse.BasicObservation{TraceID: trace.ID, Name: "quality"}, Value: 1}); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if len(eventManager.calls.Enqueue) != 0 {
+			t.Errorf("expected no events to be enqueued, got %d", len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should send the observations created on the sdk with the trace when one fails", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		sdk = langfuse.New(context.TODO(), langfuse.Options{EventManager: sdk.EventManager(), Sampler: langfuse.RatioSampler(0), SampleErrors: true})
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		_, _ = sdk.Span(context.TODO(), &langfuse.Span{BasicObservation: langfuse.BasicObservation{TraceID: trace.ID}})
+		generation, _ := sdk.Generation(context.TODO(), &langfuse.Generation{BasicObservation: langfuse.BasicObservation{TraceID: trace.ID}})
+		_ = generation.EndWithError(errors.New("failed"))
+		types := []string{langfuse.TRACE_CREATE, langfuse.SPAN_CREATE, langfuse.GENERATION_CREATE, langfuse.GENERATION_UPDATE}
+		events := flush()
+		if len(events) != len(types) {
+			t.Fatalf("expected %d events to be sent, got %d", len(types), len(events))
+		}
+		for i, eventType := range types {
+			if events[i]["type"] != eventType {
+				t.Errorf("expected event %d to be %s, got %v", i, eventType, events[i]["type"])
+			}
+		}
+	})
+}
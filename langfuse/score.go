@@ -0,0 +1,71 @@
+package langfuse
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wepala/langfuse-go/api"
+)
+
+const SCORE_NUMERIC = "NUMERIC"
+const SCORE_BOOLEAN = "BOOLEAN"
+const SCORE_CATEGORICAL = "CATEGORICAL"
+
+type Score struct {
+	BasicObservation
+	// Value of numeric and boolean scores. Boolean scores must be 0 or 1
+	Value float64 `json:"value"`
+	// StringValue is the category of categorical scores
+	StringValue   string `json:"stringValue,omitempty"`
+	DataType      string `json:"dataType,omitempty"`
+	ObservationId string `json:"observationId,omitempty"`
+	Comment       string `json:"comment,omitempty"`
+}
+
+// Validate checks that the value matches the data type. Scores without a data type are numeric unless they have a
+// string value, in which case they're categorical
+func (s *Score) Validate() error {
+	if s.TraceID == "" {
+		return errors.New("trace id is required")
+	}
+	if s.Name == "" {
+		return errors.New("name is required")
+	}
+	switch s.DataType {
+	case "":
+		return nil
+	case SCORE_NUMERIC:
+		if s.StringValue != "" {
+			return errors.New("numeric scores can't have a string value")
+		}
+	case SCORE_BOOLEAN:
+		if s.StringValue != "" {
+			return errors.New("boolean scores can't have a string value")
+		}
+		if s.Value != 0 && s.Value != 1 {
+			return fmt.Errorf("boolean scores must be 0 or 1, got %v", s.Value)
+		}
+	case SCORE_CATEGORICAL:
+		if s.StringValue == "" {
+			return errors.New("categorical scores require a string value")
+		}
+	default:
+		return fmt.Errorf("unknown score data type %s", s.DataType)
+	}
+	return nil
+}
+
+// CreateRequest returns the score as a request for the score endpoint of the api client. The value is sent as a string
+// for categorical scores and as a number otherwise
+func (s *Score) CreateRequest() *api.CreateScoreRequest {
+	body := scoreBody(s)
+	return &api.CreateScoreRequest{
+		Id:            body.Id,
+		TraceId:       body.TraceId,
+		Name:          body.Name,
+		Value:         body.Value,
+		ObservationId: body.ObservationId,
+		Comment:       body.Comment,
+		DataType:      body.DataType,
+	}
+}
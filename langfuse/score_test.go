@@ -0,0 +1,117 @@
+package langfuse_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestScore_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		score   langfuse.Score
+		invalid bool
+	}{
+		{"should allow numeric scores with decimals", langfuse.Score{Value: 0.87, DataType: langfuse.SCORE_NUMERIC}, false},
+		{"should allow scores without a data type", langfuse.Score{Value: 0.5}, false},
+		{"should allow boolean scores of 0", langfuse.Score{Value: 0, DataType: langfuse.SCORE_BOOLEAN}, false},
+		{"should not allow boolean scores other than 0 or 1", langfuse.Score{Value: 0.5, DataType: langfuse.SCORE_BOOLEAN}, true},
+		{"should allow categorical scores with a string value", langfuse.Score{StringValue: "good", DataType: langfuse.SCORE_CATEGORICAL}, false},
+		{"should not allow categorical scores without a string value", langfuse.Score{Value: 1, DataType: langfuse.SCORE_CATEGORICAL}, true},
+		{"should not allow numeric scores with a string value", langfuse.Score{StringValue: "good", DataType: langfuse.SCORE_NUMERIC}, true},
+		{"should not allow unknown data types", langfuse.Score{Value: 1, DataType: "TEXT"}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.score.TraceID = "trace-id"
+			test.score.Name = "quality"
+			err := test.score.Validate()
+			if test.invalid && err == nil {
+				t.Errorf("expected an error to be returned")
+			}
+			if !test.invalid && err != nil {
+				t.Errorf("expected no error, got %s", err)
+			}
+		})
+	}
+}
+
+func TestScore_Ingestion(t *testing.T) {
+	t.Run("should send float and zero values", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		_, err := sdk.Score(context.TODO(), &langfuse.Score{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id", Name: "similarity"}, Value: 0.87})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		_, err = sdk.Score(context.TODO(), &langfuse.Score{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id", Name: "correct"}, Value: 0, DataType: langfuse.SCORE_BOOLEAN})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		events := flush()
+		if len(events) != 2 {
+			t.Fatalf("expected %d events to be sent, got %d", 2, len(events))
+		}
+		if value := events[0]["body"].(map[string]interface{})["value"]; value != 0.87 {
+			t.Errorf("expected value to be %v, got %v", 0.87, value)
+		}
+		body := events[1]["body"].(map[string]interface{})
+		if value, ok := body["value"]; !ok || value != float64(0) {
+			t.Errorf("expected zero value to be sent, got %v", value)
+		}
+		if body["dataType"] != langfuse.SCORE_BOOLEAN {
+			t.Errorf("expected dataType to be %s, got %v", langfuse.SCORE_BOOLEAN, body["dataType"])
+		}
+	})
+	t.Run("should send categorical scores as strings", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		_, err := sdk.Score(context.TODO(), &langfuse.Score{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id", Name: "tone"}, StringValue: "friendly", DataType: langfuse.SCORE_CATEGORICAL})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		events := flush()
+		if value := events[0]["body"].(map[string]interface{})["value"]; value != "friendly" {
+			t.Errorf("expected value to be %s, got %v", "friendly", value)
+		}
+	})
+	t.Run("should not enqueue invalid scores", func(t *testing.T) {
+		eventManager := &EventManagerMock{}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager})
+		_, err := sdk.Score(context.TODO(), &langfuse.Score{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id", Name: "correct"}, Value: 2, DataType: langfuse.SCORE_BOOLEAN})
+		if err == nil {
+			t.Errorf("expected an error to be returned")
+		}
+		if len(eventManager.calls.Enqueue) != 0 {
+			t.Errorf("expected score not to be enqueued")
+		}
+	})
+	t.Run("should score observations directly", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
+		span, _ := trace.Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{ID: "span-id", Name: "retrieval"}})
+		generation, _ := span.Generation(&langfuse.Generation{BasicObservation: langfuse.BasicObservation{ID: "generation-id", Name: "answer"}})
+		if _, err := span.Score(&langfuse.Score{Value: 0.5}); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if _, err := generation.Score(&langfuse.Score{BasicObservation: langfuse.BasicObservation{Name: "faithfulness"}, Value: 0.9}); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if _, err := trace.Score(&langfuse.Score{BasicObservation: langfuse.BasicObservation{Name: "user-feedback"}, Value: 1}); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		events := flush()
+		if len(events) != 6 {
+			t.Fatalf("expected %d events to be sent, got %d", 6, len(events))
+		}
+		expected := []struct{ name, observationID interface{} }{{"retrieval", "span-id"}, {"faithfulness", "generation-id"}, {"user-feedback", nil}}
+		for i, score := range events[3:] {
+			body := score["body"].(map[string]interface{})
+			if body["traceId"] != "trace-id" {
+				t.Errorf("expected traceId to be %s, got %v", "trace-id", body["traceId"])
+			}
+			if body["name"] != expected[i].name || body["observationId"] != expected[i].observationID {
+				t.Errorf("expected score %v on %v, got %v on %v", expected[i].name, expected[i].observationID, body["name"], body["observationId"])
+			}
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package langfuse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/wepala/langfuse-go/api"
+)
+
+// Session groups the traces of a multi-turn conversation. Every trace created through the session is stamped with
+// the session id
+type Session struct {
+	ID     string
+	UserID string
+	sdk    *LangFuse
+}
+
+// Session returns a handle for creating traces in the session. If id is empty a new session id is generated
+func (l *LangFuse) Session(id string, userID string) *Session {
+	if id == "" {
+		id = ksuid.New().String()
+	}
+	return &Session{
+		ID:     id,
+		UserID: userID,
+		sdk:    l,
+	}
+}
+
+// Trace creates a trace in the session. The user of the session is used if the trace doesn't have one
+func (s *Session) Trace(ctxt context.Context, opts *Trace) (*Trace, error) {
+	if opts == nil {
+		opts = &Trace{}
+	}
+	opts.SessionID = s.ID
+	if opts.UserID == "" {
+		opts.UserID = s.UserID
+	}
+	return s.sdk.Trace(ctxt, opts)
+}
+
+// Conversation reads the session back from the api
+func (s *Session) Conversation(ctxt context.Context) (*Conversation, error) {
+	return s.sdk.Conversation(ctxt, s.ID)
+}
+
+// ConversationTurn is a single trace in a session with the generations that were made while handling it
+type ConversationTurn struct {
+	TraceID     string
+	Name        string
+	UserID      string
+	Timestamp   time.Time
+	Input       interface{}
+	Output      interface{}
+	Generations []*api.Observation
+}
+
+// Conversation is a session's traces in chronological order
+type Conversation struct {
+	SessionID string
+	CreatedAt time.Time
+	Turns     []*ConversationTurn
+}
+
+// Conversation reconstructs a session as a chronological list of turns, each with the generations of its trace
+func (l *LangFuse) Conversation(ctxt context.Context, sessionID string) (*Conversation, error) {
+	if sessionID == "" {
+		return nil, errors.New("session id is required")
+	}
+
+	session, err := l.client.Sessions.Get(ctxt, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving session %s: %w", sessionID, err)
+	}
+
+	conversation := &Conversation{
+		SessionID: session.Id,
+		CreatedAt: session.CreatedAt,
+	}
+	for _, trace := range session.Traces {
+		//the session only includes a summary of each trace so the generations need to be fetched separately
+		details, err := l.client.Trace.Get(ctxt, trace.Id)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving trace %s: %w", trace.Id, err)
+		}
+		turn := &ConversationTurn{
+			TraceID:   details.Id,
+			Name:      stringValue(details.Name),
+			UserID:    stringValue(details.UserId),
+			Timestamp: details.Timestamp,
+			Input:     deref(details.Input),
+			Output:    deref(details.Output),
+		}
+		for _, observation := range details.Observations {
+			if observation.Type == string(api.ObservationTypeGeneration) {
+				turn.Generations = append(turn.Generations, observation)
+			}
+		}
+		sort.SliceStable(turn.Generations, func(i, j int) bool {
+			return turn.Generations[i].StartTime.Before(turn.Generations[j].StartTime)
+		})
+		conversation.Turns = append(conversation.Turns, turn)
+	}
+	sort.SliceStable(conversation.Turns, func(i, j int) bool {
+		return conversation.Turns[i].Timestamp.Before(conversation.Turns[j].Timestamp)
+	})
+
+	return conversation, nil
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,91 @@
+package langfuse_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestSession_Trace(t *testing.T) {
+	t.Run("should set the session and user on every trace", func(t *testing.T) {
+		eventManager := &EventManagerMock{
+			EnqueueFunc: func(id string, eventType string, tevent interface{}) error {
+				trace := tevent.(*langfuse.Trace)
+				if trace.SessionID != "session-id" {
+					t.Errorf("expected sessionId to be %s, got %s", "session-id", trace.SessionID)
+				}
+				if trace.UserID != "user-id" {
+					t.Errorf("expected userId to be %s, got %s", "user-id", trace.UserID)
+				}
+				return nil
+			},
+		}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager})
+		session := sdk.Session("session-id", "user-id")
+		_, _ = session.Trace(context.TODO(), nil)
+		_, _ = session.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{Name: "second turn"}})
+		if len(eventManager.calls.Enqueue) != 2 {
+			t.Errorf("expected %d traces to be enqueued, got %d", 2, len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should generate a session id if none is provided", func(t *testing.T) {
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: &EventManagerMock{}})
+		if sdk.Session("", "").ID == "" {
+			t.Errorf("expected session id to be generated")
+		}
+	})
+}
+
+func TestLangFuse_Conversation(t *testing.T) {
+	t.Run("should return the turns and their generations in chronological order", func(t *testing.T) {
+		httpClient := NewTestClient(func(req *http.Request) *http.Response {
+			switch req.URL.Path {
+			case "/api/public/sessions/session-id":
+				return NewJsonResponse(http.StatusOK, map[string]interface{}{
+					"id":        "session-id",
+					"createdAt": "2024-01-01T00:00:00Z",
+					"projectId": "project-id",
+					"traces": []map[string]interface{}{
+						{"id": "second", "timestamp": "2024-01-01T00:01:00Z"},
+						{"id": "first", "timestamp": "2024-01-01T00:00:00Z"},
+					},
+				})
+			case "/api/public/traces/first":
+				return NewJsonResponse(http.StatusOK, map[string]interface{}{
+					"id": "first", "timestamp": "2024-01-01T00:00:00Z", "input": "hello", "output": "hi there",
+					"observations": []map[string]interface{}{
+						{"id": "g2", "type": "GENERATION", "startTime": "2024-01-01T00:00:02Z"},
+						{"id": "s1", "type": "SPAN", "startTime": "2024-01-01T00:00:00Z"},
+						{"id": "g1", "type": "GENERATION", "startTime": "2024-01-01T00:00:01Z"},
+					},
+				})
+			case "/api/public/traces/second":
+				return NewJsonResponse(http.StatusOK, map[string]interface{}{
+					"id": "second", "timestamp": "2024-01-01T00:01:00Z", "input": "how are you?",
+				})
+			}
+			t.Errorf("unexpected request to %s", req.URL.Path)
+			return NewStringResponse(http.StatusNotFound, "")
+		})
+		sdk := langfuse.New(context.TODO(), langfuse.Options{HttpClient: httpClient})
+		conversation, err := sdk.Conversation(context.TODO(), "session-id")
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if len(conversation.Turns) != 2 {
+			t.Fatalf("expected %d turns, got %d", 2, len(conversation.Turns))
+		}
+		if conversation.Turns[0].TraceID != "first" || conversation.Turns[1].TraceID != "second" {
+			t.Errorf("expected turns to be in chronological order")
+		}
+		if conversation.Turns[0].Output != "hi there" {
+			t.Errorf("expected output to be %s, got %v", "hi there", conversation.Turns[0].Output)
+		}
+		generations := conversation.Turns[0].Generations
+		if len(generations) != 2 || generations[0].Id != "g1" || generations[1].Id != "g2" {
+			t.Errorf("expected only generations in start time order")
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package langfuse
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+)
+
+// Storage keeps values that are too large to send with an event. The reference it returns is sent in their place
+type Storage interface {
+	Store(ctxt context.Context, data []byte, contentType string) (reference string, err error)
+}
+
+// FileStorage stores values as files in a directory. Files are named after the hash of their content so a value
+// that's stored more than once is only written once
+type FileStorage struct {
+	Dir string
+}
+
+func NewFileStorage(dir string) *FileStorage {
+	return &FileStorage{Dir: dir}
+}
+
+func (f *FileStorage) Store(ctxt context.Context, data []byte, contentType string) (string, error) {
+	if err := os.MkdirAll(f.Dir, 0o755); err != nil {
+		return "", fmt.Errorf("error creating storage directory: %w", err)
+	}
+	hash := sha256.Sum256(data)
+	name := hex.EncodeToString(hash[:])
+	if extensions, _ := mime.ExtensionsByType(contentType); len(extensions) > 0 {
+		name += extensions[0]
+	}
+	path, err := filepath.Abs(filepath.Join(f.Dir, name))
+	if err != nil {
+		return "", err
+	}
+	if _, err = os.Stat(path); os.IsNotExist(err) {
+		if err = os.WriteFile(path, data, 0o644); err != nil {
+			return "", fmt.Errorf("error storing value: %w", err)
+		}
+	}
+	return "file://" + filepath.ToSlash(path), nil
+}
@@ -0,0 +1,238 @@
+package langfuse
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/wepala/langfuse-go/api"
+)
+
+// TailSamplingOptions are the rules used to decide which traces are kept. A trace is kept as soon as it matches one
+// of the rules and is dropped if it completes or times out without matching any
+type TailSamplingOptions struct {
+	// Timeout is the longest a trace is held before it's dropped. Defaults to a minute
+	Timeout time.Duration
+	// Idle is how long a trace must go without events, with none of its spans and generations still running, before
+	// it's considered complete. Traces without spans or generations are complete once they're idle. Defaults to a
+	// second
+	Idle time.Duration
+	// Errors keeps traces with an observation at the ERROR level
+	Errors bool
+	// Latency keeps traces whose observations take longer than this from the first start to the last end
+	Latency time.Duration
+	// Scores keeps traces with a score below the threshold for the score's name. Scores are only checked while the
+	// trace is held, so a score that arrives after the trace was idle for Idle doesn't change the decision. Set Idle
+	// to longer than it takes for the scores to be created e.g. by an evaluation that runs after the response
+	Scores map[string]float64
+}
+
+// TailSamplingEventManager holds the events of each trace until it's known whether the trace is interesting enough
+// to send. Events without a trace id are sent straight away
+type TailSamplingEventManager struct {
+	eventManager EventManager
+	options      TailSamplingOptions
+	mu           sync.Mutex
+	traces       map[string]*tailTrace
+	decided      map[string]*tailDecision
+}
+
+type tailTrace struct {
+	events []bufferedEvent
+	first  time.Time
+	last   time.Time
+	open   map[string]bool
+	start  *time.Time
+	end    *time.Time
+}
+
+type tailDecision struct {
+	keep bool
+	at   time.Time
+}
+
+func NewTailSamplingEventManager(eventManager EventManager, options TailSamplingOptions) *TailSamplingEventManager {
+	if options.Timeout == 0 {
+		options.Timeout = time.Minute
+	}
+	if options.Idle == 0 {
+		options.Idle = time.Second
+	}
+	return &TailSamplingEventManager{
+		eventManager: eventManager,
+		options:      options,
+		traces:       make(map[string]*tailTrace),
+		decided:      make(map[string]*tailDecision),
+	}
+}
+
+func (t *TailSamplingEventManager) Enqueue(id string, eventType string, event interface{}) error {
+	//snapshot the event since it may change while the trace is held
+	ingestionEvent, ok, err := newIngestionEvent(id, eventType, event, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return t.eventManager.Enqueue(id, eventType, event)
+	}
+	info := describeEvent(ingestionEvent)
+	if info.traceID == "" {
+		return t.eventManager.Enqueue(id, eventType, ingestionEvent)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	//events that arrive after the decision e.g. scores from user feedback follow the rest of the trace
+	if decision, ok := t.decided[info.traceID]; ok {
+		if !decision.keep {
+			return nil
+		}
+		return t.eventManager.Enqueue(id, eventType, ingestionEvent)
+	}
+
+	now := time.Now()
+	trace, ok := t.traces[info.traceID]
+	if !ok {
+		trace = &tailTrace{first: now, open: make(map[string]bool)}
+		t.traces[info.traceID] = trace
+	}
+	trace.last = now
+	trace.events = append(trace.events, bufferedEvent{id: id, eventType: eventType, event: ingestionEvent})
+	if info.timed {
+		if info.end == nil {
+			trace.open[info.id] = true
+		} else {
+			delete(trace.open, info.id)
+		}
+	}
+	if info.start != nil && (trace.start == nil || info.start.Before(*trace.start)) {
+		trace.start = info.start
+	}
+	if info.end != nil && (trace.end == nil || info.end.After(*trace.end)) {
+		trace.end = info.end
+	}
+
+	if t.keep(trace, info) {
+		return t.decide(info.traceID, true, now)
+	}
+	return nil
+}
+
+// keep checks the rules against the trace and its latest event
+func (t *TailSamplingEventManager) keep(trace *tailTrace, info eventInfo) bool {
+	if t.options.Errors && info.level != nil && *info.level == api.ObservationLevelError {
+		return true
+	}
+	if t.options.Latency > 0 && trace.start != nil && trace.end != nil && trace.end.Sub(*trace.start) > t.options.Latency {
+		return true
+	}
+	if threshold, ok := t.options.Scores[info.scoreName]; ok && info.score != nil && *info.score < threshold {
+		return true
+	}
+	return false
+}
+
+// decide sends or drops the events held for the trace. It must be called with the lock held
+func (t *TailSamplingEventManager) decide(traceID string, keep bool, now time.Time) error {
+	trace := t.traces[traceID]
+	delete(t.traces, traceID)
+	t.decided[traceID] = &tailDecision{keep: keep, at: now}
+	if !keep || trace == nil {
+		return nil
+	}
+	for _, buffered := range trace.events {
+		if err := t.eventManager.Enqueue(buffered.id, buffered.eventType, buffered.event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sweep drops traces that are complete or have timed out. The rules are checked as events arrive so these traces
+// didn't match any. If force is set traces that don't have spans or generations running are dropped without waiting
+// for them to go idle and the traces that are still running are kept
+func (t *TailSamplingEventManager) sweep(force bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	for traceID, trace := range t.traces {
+		running := len(trace.open) != 0
+		if force {
+			_ = t.decide(traceID, running, now)
+			continue
+		}
+		if (!running && now.Sub(trace.last) >= t.options.Idle) || now.Sub(trace.first) >= t.options.Timeout {
+			_ = t.decide(traceID, false, now)
+		}
+	}
+	for traceID, decision := range t.decided {
+		if now.Sub(decision.at) >= t.options.Timeout {
+			delete(t.decided, traceID)
+		}
+	}
+}
+
+// Process makes decisions for traces that are complete and sends the kept events until the context is cancelled
+func (t *TailSamplingEventManager) Process(ctxt context.Context) {
+	for {
+		select {
+		case <-ctxt.Done():
+			return
+		default:
+			t.sweep(false)
+			t.eventManager.Flush(ctxt)
+			time.Sleep(500 * time.Millisecond)
+		}
+	}
+}
+
+// Flush decides the traces that are held, sending the traces that are still running since it isn't known yet whether
+// they match a rule, and flushes the kept events
+func (t *TailSamplingEventManager) Flush(ctxt context.Context) {
+	t.sweep(true)
+	t.eventManager.Flush(ctxt)
+}
+
+type eventInfo struct {
+	traceID   string
+	id        string
+	timed     bool
+	level     *api.ObservationLevel
+	start     *time.Time
+	end       *time.Time
+	scoreName string
+	score     *float64
+}
+
+func describeEvent(e *api.IngestionEvent) eventInfo {
+	var info eventInfo
+	switch {
+	case e.TraceCreate != nil && e.TraceCreate.Body != nil:
+		info.traceID = stringValue(e.TraceCreate.Body.Id)
+	case e.SpanCreate != nil && e.SpanCreate.Body != nil:
+		body := e.SpanCreate.Body
+		info = eventInfo{traceID: stringValue(body.TraceId), id: stringValue(body.Id), timed: true, level: body.Level, start: body.StartTime, end: body.EndTime}
+	case e.SpanUpdate != nil && e.SpanUpdate.Body != nil:
+		body := e.SpanUpdate.Body
+		info = eventInfo{traceID: stringValue(body.TraceId), id: body.Id, timed: true, level: body.Level, start: body.StartTime, end: body.EndTime}
+	case e.GenerationCreate != nil && e.GenerationCreate.Body != nil:
+		body := e.GenerationCreate.Body
+		info = eventInfo{traceID: stringValue(body.TraceId), id: stringValue(body.Id), timed: true, level: body.Level, start: body.StartTime, end: body.EndTime}
+	case e.GenerationUpdate != nil && e.GenerationUpdate.Body != nil:
+		body := e.GenerationUpdate.Body
+		info = eventInfo{traceID: stringValue(body.TraceId), id: body.Id, timed: true, level: body.Level, start: body.StartTime, end: body.EndTime}
+	case e.EventCreate != nil && e.EventCreate.Body != nil:
+		body := e.EventCreate.Body
+		info = eventInfo{traceID: stringValue(body.TraceId), id: stringValue(body.Id), level: body.Level, start: body.StartTime}
+	case e.ObservationUpdate != nil && e.ObservationUpdate.Body != nil:
+		body := e.ObservationUpdate.Body
+		info = eventInfo{traceID: stringValue(body.TraceId), id: stringValue(body.Id), level: body.Level, start: body.StartTime, end: body.EndTime}
+	case e.ScoreCreate != nil && e.ScoreCreate.Body != nil:
+		body := e.ScoreCreate.Body
+		info = eventInfo{traceID: body.TraceId, scoreName: body.Name}
+		if body.Value != nil && body.Value.String == "" {
+			info.score = &body.Value.Double
+		}
+	}
+	return info
+}
@@ -0,0 +1,113 @@
+package langfuse_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func newTailSampler(options langfuse.TailSamplingOptions) (*langfuse.LangFuse, *langfuse.TailSamplingEventManager, *EventManagerMock) {
+	eventManager := &EventManagerMock{
+		EnqueueFunc: func(id string, eventType string, event interface{}) error { return nil },
+		FlushFunc:   func(ctxt context.Context) {},
+	}
+	tailSampler := langfuse.NewTailSamplingEventManager(eventManager, options)
+	return langfuse.New(context.TODO(), langfuse.Options{EventManager: tailSampler}), tailSampler, eventManager
+}
+
+func TestTailSamplingEventManager(t *testing.T) {
+	t.Run("should send the whole trace when an observation has an error", func(t *testing.T) {
+		sdk, _, eventManager := newTailSampler(langfuse.TailSamplingOptions{Errors: true})
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		span, _ := trace.Span(nil)
+		if len(eventManager.calls.Enqueue) != 0 {
+			t.Fatalf("expected events to be held, got %d", len(eventManager.calls.Enqueue))
+		}
+		_ = span.EndWithError(errors.New("failed"))
+		_, _ = trace.Event(nil)
+		if len(eventManager.calls.Enqueue) != 4 {
+			t.Errorf("expected %d events to be sent, got %d", 4, len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should drop traces that don't match a rule when they complete", func(t *testing.T) {
+		sdk, tailSampler, eventManager := newTailSampler(langfuse.TailSamplingOptions{Errors: true, Idle: time.Millisecond})
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		span, _ := trace.Span(nil)
+		_ = span.End()
+		time.Sleep(5 * time.Millisecond)
+		ctxt, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+		tailSampler.Process(ctxt)
+		_, _ = trace.Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{Level: langfuse.LEVEL_ERROR}})
+		if len(eventManager.calls.Enqueue) != 0 {
+			t.Errorf("expected no events to be sent, got %d", len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should keep traces that are still running", func(t *testing.T) {
+		sdk, tailSampler, eventManager := newTailSampler(langfuse.TailSamplingOptions{Errors: true, Idle: time.Millisecond})
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		first, _ := trace.Span(nil)
+		_, _ = trace.Span(nil)
+		_ = first.End()
+		time.Sleep(5 * time.Millisecond)
+		ctxt, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+		tailSampler.Process(ctxt)
+		_, _ = trace.Event(&langfuse.Event{BasicObservation: langfuse.BasicObservation{Level: langfuse.LEVEL_ERROR}})
+		if len(eventManager.calls.Enqueue) != 5 {
+			t.Errorf("expected %d events to be sent, got %d", 5, len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should send traces that take longer than the latency threshold", func(t *testing.T) {
+		sdk, _, eventManager := newTailSampler(langfuse.TailSamplingOptions{Latency: time.Second})
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		span, _ := trace.Span(&langfuse.Span{StartTime: time.Now().Add(-2 * time.Second)})
+		_ = span.End()
+		if len(eventManager.calls.Enqueue) != 3 {
+			t.Errorf("expected %d events to be sent, got %d", 3, len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should send traces with a score below the threshold", func(t *testing.T) {
+		sdk, _, eventManager := newTailSampler(langfuse.TailSamplingOptions{Scores: map[string]float64{"quality": 0.5}})
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		_, _ = trace.Score(&langfuse.Score{BasicObservation: langfuse.BasicObservation{Name: "quality"}, Value: 0.8})
+		if len(eventManager.calls.Enqueue) != 0 {
+			t.Fatalf("expected events to be held, got %d", len(eventManager.calls.Enqueue))
+		}
+		_, _ = trace.Score(&langfuse.Score{BasicObservation: langfuse.BasicObservation{Name: "quality"}, Value: 0.2})
+		if len(eventManager.calls.Enqueue) != 3 {
+			t.Errorf("expected %d events to be sent, got %d", 3, len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should drop traces without spans when they go idle", func(t *testing.T) {
+		sdk, tailSampler, eventManager := newTailSampler(langfuse.TailSamplingOptions{Errors: true, Idle: time.Millisecond})
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		_, _ = trace.Event(nil)
+		time.Sleep(5 * time.Millisecond)
+		ctxt, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		defer cancel()
+		tailSampler.Process(ctxt)
+		_, _ = trace.Event(&langfuse.Event{BasicObservation: langfuse.BasicObservation{Level: langfuse.LEVEL_ERROR}})
+		if len(eventManager.calls.Enqueue) != 0 {
+			t.Errorf("expected no events to be sent, got %d", len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should send traces that are still running on flush", func(t *testing.T) {
+		sdk, tailSampler, eventManager := newTailSampler(langfuse.TailSamplingOptions{Errors: true})
+		running, _ := sdk.Trace(context.TODO(), nil)
+		_, _ = running.Span(nil)
+		complete, _ := sdk.Trace(context.TODO(), nil)
+		span, _ := complete.Span(nil)
+		_ = span.End()
+		tailSampler.Flush(context.TODO())
+		if len(eventManager.calls.Enqueue) != 2 || len(eventManager.calls.Flush) != 1 {
+			t.Fatalf("expected the running trace to be sent and the event manager to be flushed, got %d events", len(eventManager.calls.Enqueue))
+		}
+		if eventManager.calls.Enqueue[0].EventType != langfuse.TRACE_CREATE || eventManager.calls.Enqueue[1].EventType != langfuse.SPAN_CREATE {
+			t.Errorf("expected the events of the running trace to be sent")
+		}
+	})
+}
@@ -9,19 +9,19 @@ type Trace struct {
 	Version   string   `json:"version,omitempty"`
 	Release   string   `json:"release,omitempty"`
 	Tags      []string `json:"tags,omitempty"`
-	Public    bool     `json:"public"`
+	//Public is only sent when it's true so updates don't make a public trace private
+	Public bool `json:"public"`
 }
 
-func (o Trace) Update() error {
+func (o *Trace) Update() error {
 	if o.ID == "" {
 		return errors.New("trace id is not set")
 	}
 
-	o.eventManager.Enqueue("", TRACE_CREATE, o)
-	return nil
+	return o.eventManager.Enqueue("", TRACE_CREATE, o)
 }
 
-func (o Trace) Span(span *Span) (*Span, error) {
+func (o *Trace) Span(span *Span) (*Span, error) {
 	if span == nil {
 		span = &Span{}
 	}
@@ -33,7 +33,7 @@ func (o Trace) Span(span *Span) (*Span, error) {
 	return o.BasicObservation.Span(span)
 }
 
-func (o Trace) Event(event *Event) (*Event, error) {
+func (o *Trace) Event(event *Event) (*Event, error) {
 	if event == nil {
 		event = &Event{}
 	}
@@ -45,7 +45,7 @@ func (o Trace) Event(event *Event) (*Event, error) {
 	return o.BasicObservation.Event(event)
 }
 
-func (o Trace) Generation(generation *Generation) (*Generation, error) {
+func (o *Trace) Generation(generation *Generation) (*Generation, error) {
 	if generation == nil {
 		generation = &Generation{}
 	}
@@ -56,3 +56,75 @@ func (o Trace) Generation(generation *Generation) (*Generation, error) {
 
 	return o.BasicObservation.Generation(generation)
 }
+
+func (o *Trace) Score(score *Score) (*Score, error) {
+	if score == nil {
+		score = &Score{}
+	}
+
+	if score.TraceID == "" {
+		score.TraceID = o.ID
+	}
+
+	return o.BasicObservation.Score(score)
+}
+
+func (o *Trace) Tool(tool *Tool) (*Tool, error) {
+	if tool == nil {
+		tool = &Tool{}
+	}
+
+	if tool.TraceID == "" {
+		tool.TraceID = o.ID
+	}
+
+	return o.BasicObservation.Tool(tool)
+}
+
+func (o *Trace) Agent(agent *Agent) (*Agent, error) {
+	if agent == nil {
+		agent = &Agent{}
+	}
+
+	if agent.TraceID == "" {
+		agent.TraceID = o.ID
+	}
+
+	return o.BasicObservation.Agent(agent)
+}
+
+func (o *Trace) Embedding(embedding *Embedding) (*Embedding, error) {
+	if embedding == nil {
+		embedding = &Embedding{}
+	}
+
+	if embedding.TraceID == "" {
+		embedding.TraceID = o.ID
+	}
+
+	return o.BasicObservation.Embedding(embedding)
+}
+
+func (o *Trace) Retrieval(retrieval *Retrieval) (*Retrieval, error) {
+	if retrieval == nil {
+		retrieval = &Retrieval{}
+	}
+
+	if retrieval.TraceID == "" {
+		retrieval.TraceID = o.ID
+	}
+
+	return o.BasicObservation.Retrieval(retrieval)
+}
+
+func (o *Trace) Rerank(retrieval *Retrieval) (*Retrieval, error) {
+	if retrieval == nil {
+		retrieval = &Retrieval{}
+	}
+
+	if retrieval.TraceID == "" {
+		retrieval.TraceID = o.ID
+	}
+
+	return o.BasicObservation.Rerank(retrieval)
+}
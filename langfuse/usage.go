@@ -0,0 +1,275 @@
+package langfuse
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/wepala/langfuse-go/api"
+)
+
+const USAGE_GROUP_DAY = "day"
+const USAGE_GROUP_MODEL = "model"
+const USAGE_GROUP_USER = "user"
+const USAGE_GROUP_RELEASE = "release"
+const USAGE_GROUP_NAME = "name"
+
+// USAGE_UNKNOWN is the key used for usage that has no model, user or release
+const USAGE_UNKNOWN = "(none)"
+
+// usagePageSize is the number of days or traces fetched per request. Reports always cover every page
+const usagePageSize = 100
+
+// UsageRequest filters the usage in a report
+type UsageRequest struct {
+	TraceName *string
+	UserID    *string
+	//Release can only be applied to user, release and name reports since the daily metrics can't be filtered by release
+	Release *string
+	Tags    []string
+	From    *time.Time
+	To      *time.Time
+}
+
+// UsageRow is the usage and cost of a single group in a report
+type UsageRow struct {
+	Key          string
+	Traces       int
+	Observations int
+	InputUsage   int
+	OutputUsage  int
+	TotalUsage   int
+	Cost         float64
+}
+
+func (r *UsageRow) add(other *UsageRow) {
+	r.Traces += other.Traces
+	r.Observations += other.Observations
+	r.InputUsage += other.InputUsage
+	r.OutputUsage += other.OutputUsage
+	r.TotalUsage += other.TotalUsage
+	r.Cost += other.Cost
+}
+
+// UsageReport is usage and cost grouped by day, model, user, release or trace name
+type UsageReport struct {
+	GroupBy string
+	Rows    []*UsageRow
+	//usage units are only reported by the metrics endpoint, traces only include the cost
+	usage bool
+	//traces is the number of distinct traces when summing the rows would count a trace more than once
+	traces *int
+}
+
+// Total sums every row of the report. A trace that uses several models is in several rows of a model report so the
+// total of a model report uses the daily trace counts instead
+func (r *UsageReport) Total() *UsageRow {
+	total := &UsageRow{Key: "total"}
+	for _, row := range r.Rows {
+		total.add(row)
+	}
+	if r.traces != nil {
+		total.Traces = *r.traces
+	}
+	return total
+}
+
+// WriteTable renders the report as an aligned text table followed by a total row
+func (r *UsageReport) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	write := func(values []string) {
+		for _, value := range values {
+			fmt.Fprint(tw, value, "\t")
+		}
+		fmt.Fprintln(tw)
+	}
+	write(r.columns())
+	for _, row := range r.Rows {
+		write(r.values(row, 4))
+	}
+	write(r.values(r.Total(), 4))
+	return tw.Flush()
+}
+
+// WriteCSV renders the report as csv with a header row. The total row is not included
+func (r *UsageReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(r.columns()); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		if err := writer.Write(r.values(row, -1)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (r *UsageReport) columns() []string {
+	columns := []string{r.GroupBy, "traces", "observations"}
+	if r.usage {
+		columns = append(columns, "input", "output", "total")
+	}
+	return append(columns, "cost")
+}
+
+func (r *UsageReport) values(row *UsageRow, precision int) []string {
+	values := []string{row.Key, strconv.Itoa(row.Traces), strconv.Itoa(row.Observations)}
+	if r.usage {
+		values = append(values, strconv.Itoa(row.InputUsage), strconv.Itoa(row.OutputUsage), strconv.Itoa(row.TotalUsage))
+	}
+	return append(values, strconv.FormatFloat(row.Cost, 'f', precision, 64))
+}
+
+// AggregateDailyMetrics groups daily metrics by day or by model
+func AggregateDailyMetrics(days []*api.DailyMetricsDetails, groupBy string) (*UsageReport, error) {
+	report := newUsageReport(groupBy, true)
+	switch groupBy {
+	case USAGE_GROUP_DAY:
+		for _, day := range days {
+			row := report.row(day.Date)
+			row.Traces += day.CountTraces
+			row.Observations += day.CountObservations
+			row.Cost += day.TotalCost
+			for _, usage := range day.Usage {
+				row.InputUsage += usage.InputUsage
+				row.OutputUsage += usage.OutputUsage
+				row.TotalUsage += usage.TotalUsage
+			}
+		}
+	case USAGE_GROUP_MODEL:
+		//traces are counted per day so a trace that spans days is counted once for each day
+		traces := 0
+		for _, day := range days {
+			traces += day.CountTraces
+			for _, usage := range day.Usage {
+				report.row(stringValue(usage.Model)).add(&UsageRow{
+					Traces:       usage.CountTraces,
+					Observations: usage.CountObservations,
+					InputUsage:   usage.InputUsage,
+					OutputUsage:  usage.OutputUsage,
+					TotalUsage:   usage.TotalUsage,
+					Cost:         usage.TotalCost,
+				})
+			}
+		}
+		report.report.traces = &traces
+	default:
+		return nil, fmt.Errorf("daily metrics can't be grouped by %s", groupBy)
+	}
+	return report.sorted(), nil
+}
+
+// AggregateTraces groups the cost of traces by user, release or trace name. Traces only include their total cost so
+// the rows aren't split by model, use a model report with the same filters for that
+func AggregateTraces(traces []*api.TraceWithDetails, groupBy string) (*UsageReport, error) {
+	var key func(trace *api.TraceWithDetails) *string
+	switch groupBy {
+	case USAGE_GROUP_USER:
+		key = func(trace *api.TraceWithDetails) *string { return trace.UserId }
+	case USAGE_GROUP_RELEASE:
+		key = func(trace *api.TraceWithDetails) *string { return trace.Release }
+	case USAGE_GROUP_NAME:
+		key = func(trace *api.TraceWithDetails) *string { return trace.Name }
+	default:
+		return nil, fmt.Errorf("traces can't be grouped by %s", groupBy)
+	}
+	report := newUsageReport(groupBy, false)
+	for _, trace := range traces {
+		row := report.row(stringValue(key(trace)))
+		row.Traces++
+		row.Observations += len(trace.Observations)
+		if trace.TotalCost != nil {
+			row.Cost += *trace.TotalCost
+		}
+	}
+	return report.sorted(), nil
+}
+
+// Usage builds a usage report for the filters in the request. Day and model reports are built from the daily metrics
+// endpoint, user, release and name reports from the total cost of each trace
+func (l *LangFuse) Usage(ctxt context.Context, request *UsageRequest, groupBy string) (*UsageReport, error) {
+	if request == nil {
+		request = &UsageRequest{}
+	}
+	var tags []*string
+	for i := range request.Tags {
+		tags = append(tags, &request.Tags[i])
+	}
+	switch groupBy {
+	case USAGE_GROUP_DAY, USAGE_GROUP_MODEL:
+		if request.Release != nil {
+			return nil, fmt.Errorf("%s reports can't be filtered by release", groupBy)
+		}
+		days, err := l.DailyMetrics(ctxt, &api.MetricsDailyRequest{
+			Limit:         api.Int(usagePageSize),
+			TraceName:     request.TraceName,
+			UserId:        request.UserID,
+			Tags:          tags,
+			FromTimestamp: request.From,
+			ToTimestamp:   request.To,
+		}, nil).All()
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving daily metrics: %w", err)
+		}
+		return AggregateDailyMetrics(days, groupBy)
+	case USAGE_GROUP_USER, USAGE_GROUP_RELEASE, USAGE_GROUP_NAME:
+		traces, err := l.ListTraces(ctxt, &api.TraceListRequest{
+			Limit:         api.Int(usagePageSize),
+			Name:          request.TraceName,
+			UserId:        request.UserID,
+			Release:       request.Release,
+			Tags:          tags,
+			FromTimestamp: request.From,
+			ToTimestamp:   request.To,
+		}, nil).All()
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving traces: %w", err)
+		}
+		return AggregateTraces(traces, groupBy)
+	}
+	return nil, fmt.Errorf("unsupported usage group %s", groupBy)
+}
+
+type usageReportBuilder struct {
+	report *UsageReport
+	rows   map[string]*UsageRow
+}
+
+func newUsageReport(groupBy string, usage bool) *usageReportBuilder {
+	return &usageReportBuilder{
+		report: &UsageReport{GroupBy: groupBy, usage: usage},
+		rows:   make(map[string]*UsageRow),
+	}
+}
+
+func (b *usageReportBuilder) row(key string) *UsageRow {
+	if key == "" {
+		key = USAGE_UNKNOWN
+	}
+	row, ok := b.rows[key]
+	if !ok {
+		row = &UsageRow{Key: key}
+		b.rows[key] = row
+		b.report.Rows = append(b.report.Rows, row)
+	}
+	return row
+}
+
+// sorted orders days chronologically and every other group by descending cost
+func (b *usageReportBuilder) sorted() *UsageReport {
+	rows := b.report.Rows
+	sort.SliceStable(rows, func(i, j int) bool {
+		if b.report.GroupBy != USAGE_GROUP_DAY && rows[i].Cost != rows[j].Cost {
+			return rows[i].Cost > rows[j].Cost
+		}
+		return rows[i].Key < rows[j].Key
+	})
+	return b.report
+}
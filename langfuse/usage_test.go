@@ -0,0 +1,231 @@
+package langfuse_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/wepala/langfuse-go/api"
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func dailyMetrics() []*api.DailyMetricsDetails {
+	return []*api.DailyMetricsDetails{
+		{
+			Date:              "2024-05-02",
+			CountTraces:       3,
+			CountObservations: 6,
+			TotalCost:         0.5,
+			Usage: []*api.UsageByModel{
+				{Model: api.String("gpt-4o"), InputUsage: 100, OutputUsage: 50, TotalUsage: 150, CountTraces: 2, CountObservations: 4, TotalCost: 0.4},
+				{Model: api.String("gpt-4o-mini"), InputUsage: 10, OutputUsage: 5, TotalUsage: 15, CountTraces: 1, CountObservations: 2, TotalCost: 0.1},
+			},
+		},
+		{
+			Date:              "2024-05-01",
+			CountTraces:       1,
+			CountObservations: 1,
+			TotalCost:         0.25,
+			Usage: []*api.UsageByModel{
+				{Model: api.String("gpt-4o-mini"), InputUsage: 20, OutputUsage: 10, TotalUsage: 30, CountTraces: 1, CountObservations: 1, TotalCost: 0.25},
+			},
+		},
+	}
+}
+
+func TestAggregateDailyMetrics(t *testing.T) {
+	t.Run("should sum usage and cost per model ordered by cost", func(t *testing.T) {
+		report, err := langfuse.AggregateDailyMetrics(dailyMetrics(), langfuse.USAGE_GROUP_MODEL)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if len(report.Rows) != 2 {
+			t.Fatalf("expected %d rows, got %d", 2, len(report.Rows))
+		}
+		mini := report.Rows[1]
+		if report.Rows[0].Key != "gpt-4o" || mini.Key != "gpt-4o-mini" {
+			t.Errorf("expected rows to be ordered by cost, got %s, %s", report.Rows[0].Key, mini.Key)
+		}
+		if mini.TotalUsage != 45 || mini.InputUsage != 30 || mini.Traces != 2 || mini.Observations != 3 {
+			t.Errorf("expected usage across days to be summed, got %+v", mini)
+		}
+		if mini.Cost != 0.35 {
+			t.Errorf("expected cost %f, got %f", 0.35, mini.Cost)
+		}
+	})
+	t.Run("should order days chronologically", func(t *testing.T) {
+		report, err := langfuse.AggregateDailyMetrics(dailyMetrics(), langfuse.USAGE_GROUP_DAY)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if report.Rows[0].Key != "2024-05-01" || report.Rows[1].Key != "2024-05-02" {
+			t.Errorf("expected days in order, got %s, %s", report.Rows[0].Key, report.Rows[1].Key)
+		}
+		if report.Rows[1].TotalUsage != 165 || report.Rows[1].Traces != 3 {
+			t.Errorf("expected the day totals to be used, got %+v", report.Rows[1])
+		}
+	})
+	t.Run("should group usage without a model as unknown", func(t *testing.T) {
+		report, _ := langfuse.AggregateDailyMetrics([]*api.DailyMetricsDetails{{Usage: []*api.UsageByModel{{TotalCost: 1}}}}, langfuse.USAGE_GROUP_MODEL)
+		if report.Rows[0].Key != langfuse.USAGE_UNKNOWN {
+			t.Errorf("expected key %s, got %s", langfuse.USAGE_UNKNOWN, report.Rows[0].Key)
+		}
+	})
+	t.Run("should count a trace that uses several models once in the total", func(t *testing.T) {
+		report, _ := langfuse.AggregateDailyMetrics([]*api.DailyMetricsDetails{{
+			CountTraces: 1,
+			Usage: []*api.UsageByModel{
+				{Model: api.String("gpt-4o"), CountTraces: 1},
+				{Model: api.String("text-embedding-3-small"), CountTraces: 1},
+			},
+		}}, langfuse.USAGE_GROUP_MODEL)
+		if traces := report.Total().Traces; traces != 1 {
+			t.Errorf("expected %d trace in the total, got %d", 1, traces)
+		}
+	})
+	t.Run("should return an error for groups the metrics don't include", func(t *testing.T) {
+		if _, err := langfuse.AggregateDailyMetrics(dailyMetrics(), langfuse.USAGE_GROUP_USER); err == nil {
+			t.Errorf("expected an error to be returned")
+		}
+	})
+}
+
+func TestAggregateTraces(t *testing.T) {
+	traces := []*api.TraceWithDetails{
+		{Id: "1", UserId: api.String("alice"), Release: api.String("v1"), TotalCost: api.Float64(0.2), Observations: []string{"a", "b"}},
+		{Id: "2", UserId: api.String("bob"), Release: api.String("v1"), TotalCost: api.Float64(0.5)},
+		{Id: "3", UserId: api.String("alice"), Release: api.String("v2"), TotalCost: api.Float64(0.4)},
+		{Id: "4"},
+	}
+	t.Run("should sum the cost per user", func(t *testing.T) {
+		report, err := langfuse.AggregateTraces(traces, langfuse.USAGE_GROUP_USER)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if len(report.Rows) != 3 {
+			t.Fatalf("expected %d rows, got %d", 3, len(report.Rows))
+		}
+		alice := report.Rows[0]
+		if alice.Key != "alice" || alice.Traces != 2 || alice.Observations != 2 || alice.Cost < 0.599 || alice.Cost > 0.601 {
+			t.Errorf("expected alice to have 2 traces costing 0.6, got %+v", alice)
+		}
+		if report.Rows[2].Key != langfuse.USAGE_UNKNOWN {
+			t.Errorf("expected traces without a user to be grouped as unknown, got %s", report.Rows[2].Key)
+		}
+	})
+	t.Run("should sum the cost per release", func(t *testing.T) {
+		report, _ := langfuse.AggregateTraces(traces, langfuse.USAGE_GROUP_RELEASE)
+		if report.Rows[0].Key != "v1" || report.Rows[0].Traces != 2 {
+			t.Errorf("expected release v1 to have 2 traces, got %+v", report.Rows[0])
+		}
+	})
+}
+
+func TestUsageReport_Write(t *testing.T) {
+	report, _ := langfuse.AggregateDailyMetrics(dailyMetrics(), langfuse.USAGE_GROUP_MODEL)
+	t.Run("should render an aligned table with a total row", func(t *testing.T) {
+		var out bytes.Buffer
+		if err := report.WriteTable(&out); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+		if len(lines) != 4 {
+			t.Fatalf("expected header, 2 rows and a total, got\n%s", out.String())
+		}
+		if fields := strings.Fields(lines[0]); strings.Join(fields, ",") != "model,traces,observations,input,output,total,cost" {
+			t.Errorf("unexpected header %s", lines[0])
+		}
+		if fields := strings.Fields(lines[3]); strings.Join(fields, ",") != "total,4,7,130,65,195,0.7500" {
+			t.Errorf("unexpected total row %s", lines[3])
+		}
+		if len(lines[1]) != len(lines[2]) {
+			t.Errorf("expected columns to be aligned\n%s", out.String())
+		}
+	})
+	t.Run("should render csv without the usage columns for trace reports", func(t *testing.T) {
+		traces, _ := langfuse.AggregateTraces([]*api.TraceWithDetails{{Name: api.String("search"), TotalCost: api.Float64(0.125)}}, langfuse.USAGE_GROUP_NAME)
+		var out bytes.Buffer
+		if err := traces.WriteCSV(&out); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		expected := "name,traces,observations,cost\nsearch,1,0,0.125\n"
+		if out.String() != expected {
+			t.Errorf("expected %q got %q", expected, out.String())
+		}
+	})
+}
+
+func TestLangFuse_Usage(t *testing.T) {
+	t.Run("should aggregate every page of daily metrics by model", func(t *testing.T) {
+		httpClient := NewTestClient(func(req *http.Request) *http.Response {
+			if req.URL.Path != "/api/public/metrics/daily" {
+				t.Errorf("expected the metrics endpoint to be called, got %s", req.URL.Path)
+			}
+			if req.URL.Query().Get("traceName") != "search" {
+				t.Errorf("expected the trace name filter to be sent")
+			}
+			days := dailyMetrics()
+			page := 0
+			if req.URL.Query().Get("page") == "2" {
+				page = 1
+			}
+			return NewJsonResponse(http.StatusOK, map[string]interface{}{
+				"data": []*api.DailyMetricsDetails{days[page]},
+				"meta": map[string]interface{}{"page": page + 1, "limit": 1, "totalItems": 2, "totalPages": 2},
+			})
+		})
+		sdk := langfuse.New(context.TODO(), langfuse.Options{HttpClient: httpClient})
+		report, err := sdk.Usage(context.TODO(), &langfuse.UsageRequest{TraceName: api.String("search")}, langfuse.USAGE_GROUP_MODEL)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if total := report.Total(); total.TotalUsage != 195 {
+			t.Errorf("expected usage from both pages, got %d", total.TotalUsage)
+		}
+	})
+	t.Run("should aggregate traces by user with the filters", func(t *testing.T) {
+		httpClient := NewTestClient(func(req *http.Request) *http.Response {
+			if req.URL.Path != "/api/public/traces" {
+				t.Errorf("expected the traces endpoint to be called, got %s", req.URL.Path)
+			}
+			if req.URL.Query().Get("tags") != "feature:search" {
+				t.Errorf("expected the tag filter to be sent")
+			}
+			if req.URL.Query().Get("release") != "v2" {
+				t.Errorf("expected the release filter to be sent")
+			}
+			if req.URL.Query().Get("limit") != "100" {
+				t.Errorf("expected a fixed page size to be used, got %s", req.URL.Query().Get("limit"))
+			}
+			return NewJsonResponse(http.StatusOK, map[string]interface{}{
+				"data": []map[string]interface{}{
+					{"id": "1", "timestamp": "2024-05-01T00:00:00Z", "userId": "alice", "totalCost": 0.3},
+					{"id": "2", "timestamp": "2024-05-01T00:00:00Z", "userId": "bob", "totalCost": 0.1},
+				},
+				"meta": map[string]interface{}{"page": 1, "limit": 50, "totalItems": 2, "totalPages": 1},
+			})
+		})
+		sdk := langfuse.New(context.TODO(), langfuse.Options{HttpClient: httpClient})
+		report, err := sdk.Usage(context.TODO(), &langfuse.UsageRequest{Tags: []string{"feature:search"}, Release: api.String("v2")}, langfuse.USAGE_GROUP_USER)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if len(report.Rows) != 2 || report.Rows[0].Key != "alice" || report.Rows[0].Cost != 0.3 {
+			t.Errorf("expected alice to be the most expensive user, got %+v", report.Rows)
+		}
+	})
+	t.Run("should return an error for an unsupported group", func(t *testing.T) {
+		sdk := langfuse.New(context.TODO(), langfuse.Options{HttpClient: NewTestClient(nil)})
+		if _, err := sdk.Usage(context.TODO(), nil, "region"); err == nil {
+			t.Errorf("expected an error to be returned")
+		}
+	})
+	t.Run("should return an error if a model report is filtered by release", func(t *testing.T) {
+		sdk := langfuse.New(context.TODO(), langfuse.Options{HttpClient: NewTestClient(nil)})
+		if _, err := sdk.Usage(context.TODO(), &langfuse.UsageRequest{Release: api.String("v2")}, langfuse.USAGE_GROUP_MODEL); err == nil {
+			t.Errorf("expected an error to be returned")
+		}
+	})
+}
@@ -0,0 +1,191 @@
+package langfuse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/wepala/langfuse-go/api"
+)
+
+// ValidateIngestionEvent checks an ingestion event in its json form against the ingestion schema in api.yaml. Unknown
+// fields, values of the wrong type, fields the schema requires for the event type that are missing and values that
+// aren't in an enum are errors
+func ValidateIngestionEvent(data []byte) error {
+	var envelope struct {
+		ID        string          `json:"id"`
+		Type      string          `json:"type"`
+		Timestamp string          `json:"timestamp"`
+		Metadata  interface{}     `json:"metadata"`
+		Body      json.RawMessage `json:"body"`
+	}
+	if err := strictUnmarshal(data, &envelope); err != nil {
+		return fmt.Errorf("invalid ingestion event: %w", err)
+	}
+	if envelope.ID == "" {
+		return errors.New("invalid ingestion event: id is required")
+	}
+	if _, err := time.Parse(time.RFC3339Nano, envelope.Timestamp); err != nil {
+		return fmt.Errorf("invalid %s event %s: timestamp %q is not an RFC 3339 date", envelope.Type, envelope.ID, envelope.Timestamp)
+	}
+	if len(envelope.Body) == 0 || string(envelope.Body) == "null" {
+		return fmt.Errorf("invalid %s event %s: body is required", envelope.Type, envelope.ID)
+	}
+	if err := validateBody(envelope.Type, envelope.Body); err != nil {
+		return fmt.Errorf("invalid %s event %s: %w", envelope.Type, envelope.ID, err)
+	}
+	return nil
+}
+
+func validateBody(eventType string, data []byte) error {
+	var level *api.ObservationLevel
+	switch eventType {
+	case TRACE_CREATE:
+		return strictUnmarshal(data, &api.TraceBody{})
+	case SCORE_CREATE:
+		body := &api.ScoreBody{}
+		if err := strictUnmarshal(data, body); err != nil {
+			return err
+		}
+		if body.TraceId == "" || body.Name == "" || body.Value == nil {
+			return errors.New("traceId, name and value are required")
+		}
+		if body.DataType != nil {
+			if _, err := api.NewScoreDataTypeFromString(string(*body.DataType)); err != nil {
+				return fmt.Errorf("dataType %q is not one of NUMERIC, BOOLEAN or CATEGORICAL", *body.DataType)
+			}
+		}
+		return nil
+	case EVENT_CREATE:
+		body := &api.CreateEventBody{}
+		if err := strictUnmarshal(data, body); err != nil {
+			return err
+		}
+		level = body.Level
+	case SPAN_CREATE:
+		body := &api.CreateSpanBody{}
+		if err := strictUnmarshal(data, body); err != nil {
+			return err
+		}
+		level = body.Level
+	case SPAN_UPDATE:
+		body := &api.UpdateSpanBody{}
+		if err := strictUnmarshal(data, body); err != nil {
+			return err
+		}
+		if body.Id == "" {
+			return errors.New("id is required")
+		}
+		level = body.Level
+	case GENERATION_CREATE:
+		body := &api.CreateGenerationBody{}
+		if err := strictUnmarshal(data, body); err != nil {
+			return err
+		}
+		level = body.Level
+	case GENERATION_UPDATE:
+		body := &api.UpdateGenerationBody{}
+		if err := strictUnmarshal(data, body); err != nil {
+			return err
+		}
+		if body.Id == "" {
+			return errors.New("id is required")
+		}
+		level = body.Level
+	case OBSERVATION_CREATE, OBSERVATION_UPDATE:
+		body := &api.ObservationBody{}
+		if err := strictUnmarshal(data, body); err != nil {
+			return err
+		}
+		if _, err := api.NewObservationTypeFromString(string(body.Type)); err != nil {
+			return fmt.Errorf("type %q is not one of SPAN, GENERATION or EVENT", body.Type)
+		}
+		level = body.Level
+	case SDK_LOG:
+		body := &api.SdkLogBody{}
+		if err := strictUnmarshal(data, body); err != nil {
+			return err
+		}
+		if body.Log == nil {
+			return errors.New("log is required")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown event type %q", eventType)
+	}
+	if level != nil {
+		if _, err := api.NewObservationLevelFromString(string(*level)); err != nil {
+			return fmt.Errorf("level %q is not one of DEBUG, DEFAULT, WARNING or ERROR", *level)
+		}
+	}
+	return nil
+}
+
+func strictUnmarshal(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after the json value")
+	}
+	return nil
+}
+
+// validatingEventManager rejects events that don't match the ingestion schema before passing them to the next event
+// manager
+type validatingEventManager struct {
+	eventManager EventManager
+}
+
+func newValidatingEventManager(eventManager EventManager) *validatingEventManager {
+	return &validatingEventManager{eventManager: eventManager}
+}
+
+func (v *validatingEventManager) Enqueue(id string, eventType string, event interface{}) error {
+	if id == "" {
+		id = ksuid.New().String()
+	}
+	timestamp := time.Now()
+	ingestionEvent, ok, err := newIngestionEvent(id, eventType, event, timestamp)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	if ok {
+		data, err = json.Marshal(ingestionEvent)
+	} else {
+		//events the sdk doesn't know are checked in the form the batch event manager sends them
+		data, err = json.Marshal(map[string]interface{}{
+			"id":        id,
+			"type":      eventType,
+			"timestamp": timestamp.UTC().Format(time.RFC3339Nano),
+			"body":      event,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s event %s: %w", eventType, id, err)
+	}
+	if err = ValidateIngestionEvent(data); err != nil {
+		return err
+	}
+	if ok {
+		return v.eventManager.Enqueue(id, eventType, ingestionEvent)
+	}
+	return v.eventManager.Enqueue(id, eventType, event)
+}
+
+func (v *validatingEventManager) Flush(ctxt context.Context) {
+	v.eventManager.Flush(ctxt)
+}
+
+func (v *validatingEventManager) Process(ctxt context.Context) {
+	if processor, ok := v.eventManager.(interface{ Process(ctxt context.Context) }); ok {
+		processor.Process(ctxt)
+	}
+}
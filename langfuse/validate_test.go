@@ -0,0 +1,146 @@
+package langfuse_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestValidateIngestionEvent(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+		err   string
+	}{
+		{"should accept a valid event", `{"id":"1","type":"span-create","timestamp":"2024-01-01T00:00:00Z","body":{"id":"span-id","traceId":"trace-id","startTime":"2024-01-01T00:00:00Z"}}`, ""},
+		{"should accept observations without a trace id", `{"id":"1","type":"generation-create","timestamp":"2024-01-01T00:00:00Z","body":{"id":"generation-id"}}`, ""},
+		{"should reject unknown fields", `{"id":"1","type":"event-create","timestamp":"2024-01-01T00:00:00Z","body":{"id":"event-id","start_time":"2024-01-01T00:00:00Z"}}`, `unknown field "start_time"`},
+		{"should reject values of the wrong type", `{"id":"1","type":"generation-create","timestamp":"2024-01-01T00:00:00Z","body":{"id":"generation-id","promptVersion":"2"}}`, "promptVersion"},
+		{"should require the id of updates", `{"id":"1","type":"span-update","timestamp":"2024-01-01T00:00:00Z","body":{"name":"retrieve"}}`, "id is required"},
+		{"should require the log of sdk logs", `{"id":"1","type":"sdk-log","timestamp":"2024-01-01T00:00:00Z","body":{}}`, "log is required"},
+		{"should require the score value", `{"id":"1","type":"score-create","timestamp":"2024-01-01T00:00:00Z","body":{"traceId":"trace-id","name":"quality"}}`, "value are required"},
+		{"should reject levels that aren't in the enum", `{"id":"1","type":"span-create","timestamp":"2024-01-01T00:00:00Z","body":{"id":"span-id","traceId":"trace-id","level":"FATAL"}}`, `level "FATAL"`},
+		{"should reject unknown event types", `{"id":"1","type":"span-delete","timestamp":"2024-01-01T00:00:00Z","body":{}}`, "unknown event type"},
+		{"should reject invalid timestamps", `{"id":"1","type":"trace-create","timestamp":"yesterday","body":{"id":"trace-id"}}`, "timestamp"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := langfuse.ValidateIngestionEvent([]byte(test.event))
+			if test.err == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("expected error to contain %s, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestLangFuse_Strict(t *testing.T) {
+	t.Run("should return an error for events that don't match the schema", func(t *testing.T) {
+		eventManager := &EventManagerMock{
+			EnqueueFunc: func(id string, eventType string, event interface{}) error {
+				return nil
+			},
+		}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager, Strict: true})
+		err := sdk.EventManager().Enqueue("", langfuse.EVENT_CREATE, map[string]interface{}{"id": "event-id", "start_time": "2024-01-01T00:00:00Z"})
+		if err == nil || !strings.Contains(err.Error(), "start_time") {
+			t.Errorf("expected the unknown field to be reported, got %v", err)
+		}
+		span, _ := sdk.Span(context.TODO(), &langfuse.Span{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id"}})
+		span.SetLevel("FATAL")
+		if err = span.End(); err == nil {
+			t.Errorf("expected the invalid level to be reported")
+		}
+		if len(eventManager.calls.Enqueue) != 1 {
+			t.Errorf("expected only the valid event to be enqueued, got %d", len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should accept the events the sdk sends", func(t *testing.T) {
+		eventManager := &EventManagerMock{
+			EnqueueFunc: func(id string, eventType string, event interface{}) error {
+				return nil
+			},
+		}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager, Strict: true})
+		trace, err := sdk.Session("session-id", "user-id").Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{Name: "chat"}})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		span, err := trace.Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{Name: "retrieve"}})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		generation, err := span.Generation(&langfuse.Generation{Model: "gpt-4o", Usage: map[string]interface{}{"promptTokens": 10}})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if err = generation.EndWithOutput("hello"); err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+		event, err := span.Event(nil)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if err = event.UpdateWithError(errors.New("failed")); err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+		if err = span.End(); err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+		if _, err = trace.Score(&langfuse.Score{BasicObservation: langfuse.BasicObservation{Name: "quality"}, Value: 0.5}); err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+		if len(eventManager.calls.Enqueue) != 8 {
+			t.Errorf("expected %d events to be enqueued, got %d", 8, len(eventManager.calls.Enqueue))
+		}
+	})
+	t.Run("should accept observations created on the sdk without a trace", func(t *testing.T) {
+		eventManager := &EventManagerMock{
+			EnqueueFunc: func(id string, eventType string, event interface{}) error {
+				return nil
+			},
+		}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager, Strict: true})
+		if _, err := sdk.Span(context.TODO(), &langfuse.Span{}); err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+	})
+	t.Run("should accept an event marshalled to json", func(t *testing.T) {
+		event := langfuse.Event{BasicObservation: langfuse.BasicObservation{ID: "event-id", TraceID: "trace-id"}, StartTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+		body, err := json.Marshal(event)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		data := fmt.Sprintf(`{"id":"1","type":"event-create","timestamp":"2024-01-01T00:00:00Z","body":%s}`, body)
+		if err = langfuse.ValidateIngestionEvent([]byte(data)); err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+		var decoded langfuse.Event
+		if err = json.Unmarshal(body, &decoded); err != nil || !decoded.StartTime.Equal(event.StartTime) {
+			t.Errorf("expected the start time to be decoded, got %v %v", decoded.StartTime, err)
+		}
+	})
+	t.Run("should not check events when it's not strict", func(t *testing.T) {
+		eventManager := &EventManagerMock{
+			EnqueueFunc: func(id string, eventType string, event interface{}) error {
+				return nil
+			},
+		}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager})
+		err := sdk.EventManager().Enqueue("", langfuse.EVENT_CREATE, map[string]interface{}{"start_time": "2024-01-01T00:00:00Z"})
+		if err != nil || len(eventManager.calls.Enqueue) != 1 {
+			t.Errorf("expected the event to be enqueued, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package langfuse
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+)
+
+// Parent is an observation that spans and generations can be nested under e.g. a *Trace or *Span
+type Parent interface {
+	Span(opts *Span) (*Span, error)
+	Generation(opts *Generation) (*Generation, error)
+}
+
+type parentKey struct{}
+
+// ContextWithParent returns a context that observations created with WithSpan and WithGeneration will be nested under
+func ContextWithParent(ctxt context.Context, parent Parent) context.Context {
+	return context.WithValue(ctxt, parentKey{}, parent)
+}
+
+// ParentFromContext returns the observation set with ContextWithParent. Inside WithSpan and WithGeneration this is
+// the span or generation that was created for the function
+func ParentFromContext(ctxt context.Context) (Parent, bool) {
+	parent, ok := ctxt.Value(parentKey{}).(Parent)
+	return parent, ok && parent != nil
+}
+
+// WithSpan runs fn in a span nested under the parent in the context. The value fn returns is recorded as the output
+// and errors and panics are recorded with the ERROR level. Panics are re-raised after the span is ended. fn is always
+// run and only its result is returned, if there is no parent in the context or the span can't be created fn is run
+// without a span
+func WithSpan[T any](ctxt context.Context, name string, fn func(ctxt context.Context) (T, error)) (T, error) {
+	parent, ok := ParentFromContext(ctxt)
+	if !ok {
+		return fn(ctxt)
+	}
+	span, err := parent.Span(&Span{BasicObservation: BasicObservation{Name: name}})
+	if err != nil || span == nil {
+		return fn(ctxt)
+	}
+	return run(ctxt, span, span.EndWithOutput, span.EndWithError, fn)
+}
+
+// WithGeneration runs fn in a generation nested under the parent in the context. See WithSpan
+func WithGeneration[T any](ctxt context.Context, opts *Generation, fn func(ctxt context.Context) (T, error)) (T, error) {
+	parent, ok := ParentFromContext(ctxt)
+	if !ok {
+		return fn(ctxt)
+	}
+	generation, err := parent.Generation(opts)
+	if err != nil || generation == nil {
+		return fn(ctxt)
+	}
+	return run(ctxt, generation, generation.EndWithOutput, generation.EndWithError, fn)
+}
+
+func run[T any](ctxt context.Context, observation Parent, endWithOutput func(interface{}) error, endWithError func(error) error, fn func(ctxt context.Context) (T, error)) (T, error) {
+	defer func() {
+		if r := recover(); r != nil {
+			_ = endWithError(fmt.Errorf("panic: %v\n%s", r, debug.Stack()))
+			panic(r)
+		}
+	}()
+	result, err := fn(ContextWithParent(ctxt, observation))
+	//errors ending the observation aren't returned so tracing can't change the outcome of fn
+	if err != nil {
+		_ = endWithError(err)
+		return result, err
+	}
+	_ = endWithOutput(result)
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package langfuse_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestWithSpan(t *testing.T) {
+	t.Run("should record the result as the output of the span", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
+		ctxt := langfuse.ContextWithParent(context.TODO(), trace)
+		result, err := langfuse.WithSpan(ctxt, "retrieve", func(ctxt context.Context) ([]string, error) {
+			return []string{"doc"}, nil
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if len(result) != 1 {
+			t.Errorf("expected the result to be returned, got %v", result)
+		}
+		events := flush()
+		if len(events) != 3 {
+			t.Fatalf("expected %d events to be sent, got %d", 3, len(events))
+		}
+		body := events[2]["body"].(map[string]interface{})
+		if body["name"] != "retrieve" || body["traceId"] != "trace-id" || body["endTime"] == nil {
+			t.Errorf("expected span to be ended, got %v", body)
+		}
+		if output, ok := body["output"].([]interface{}); !ok || output[0] != "doc" {
+			t.Errorf("expected output to be the result, got %v", body["output"])
+		}
+	})
+	t.Run("should nest observations created in the function", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
+		ctxt := langfuse.ContextWithParent(context.TODO(), trace)
+		var spanID string
+		_, err := langfuse.WithSpan(ctxt, "agent", func(ctxt context.Context) (string, error) {
+			parent, _ := langfuse.ParentFromContext(ctxt)
+			spanID = parent.(*langfuse.Span).ID
+			return langfuse.WithGeneration(ctxt, &langfuse.Generation{Model: "gpt-4"}, func(ctxt context.Context) (string, error) {
+				return "answer", nil
+			})
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		events := flush()
+		generation := events[2]["body"].(map[string]interface{})
+		if events[2]["type"] != langfuse.GENERATION_CREATE || generation["parentObservationId"] != spanID {
+			t.Errorf("expected generation to be nested under the span, got %v", generation)
+		}
+	})
+	t.Run("should record errors", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		ctxt := langfuse.ContextWithParent(context.TODO(), trace)
+		_, err := langfuse.WithSpan(ctxt, "call", func(ctxt context.Context) (int, error) {
+			return 0, errors.New("unavailable")
+		})
+		if err == nil || err.Error() != "unavailable" {
+			t.Errorf("expected the error to be returned, got %v", err)
+		}
+		body := flush()[2]["body"].(map[string]interface{})
+		if body["level"] != langfuse.LEVEL_ERROR || body["statusMessage"] != "unavailable" {
+			t.Errorf("expected the error to be recorded, got %v", body)
+		}
+	})
+	t.Run("should record panics with the stack and re-panic", func(t *testing.T) {
+		sdk, flush := captureBatch(t)
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		ctxt := langfuse.ContextWithParent(context.TODO(), trace)
+		func() {
+			defer func() {
+				if r := recover(); r != "boom" {
+					t.Errorf("expected the panic to be re-raised, got %v", r)
+				}
+			}()
+			_, _ = langfuse.WithSpan(ctxt, "call", func(ctxt context.Context) (int, error) {
+				panic("boom")
+			})
+		}()
+		body := flush()[2]["body"].(map[string]interface{})
+		message, _ := body["statusMessage"].(string)
+		if body["level"] != langfuse.LEVEL_ERROR || !strings.HasPrefix(message, "panic: boom") || !strings.Contains(message, "goroutine") {
+			t.Errorf("expected the panic to be recorded with the stack, got %v", message)
+		}
+	})
+	t.Run("should run the function without a span if there is no parent in the context", func(t *testing.T) {
+		result, err := langfuse.WithSpan(context.TODO(), "call", func(ctxt context.Context) (int, error) {
+			if _, ok := langfuse.ParentFromContext(ctxt); ok {
+				t.Errorf("expected no parent in the context")
+			}
+			return 1, nil
+		})
+		if err != nil || result != 1 {
+			t.Errorf("expected the result of the function to be returned, got %d %v", result, err)
+		}
+	})
+	t.Run("should run the function without a span if the span can't be created", func(t *testing.T) {
+		eventManager := &EventManagerMock{
+			EnqueueFunc: func(id string, eventType string, event interface{}) error {
+				if eventType == langfuse.GENERATION_CREATE {
+					return errors.New("queue full")
+				}
+				return nil
+			},
+		}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager})
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{})
+		ctxt := langfuse.ContextWithParent(context.TODO(), trace)
+		result, err := langfuse.WithGeneration(ctxt, &langfuse.Generation{}, func(ctxt context.Context) (int, error) {
+			if parent, _ := langfuse.ParentFromContext(ctxt); parent != trace {
+				t.Errorf("expected the function to be run under the original parent")
+			}
+			return 1, nil
+		})
+		if err != nil || result != 1 {
+			t.Errorf("expected the result of the function to be returned, got %d %v", result, err)
+		}
+	})
+	t.Run("should not return errors from ending the span", func(t *testing.T) {
+		eventManager := &EventManagerMock{
+			EnqueueFunc: func(id string, eventType string, event interface{}) error {
+				if eventType == langfuse.SPAN_UPDATE {
+					return errors.New("queue full")
+				}
+				return nil
+			},
+		}
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager})
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{})
+		result, err := langfuse.WithSpan(langfuse.ContextWithParent(context.TODO(), trace), "call", func(ctxt context.Context) (string, error) {
+			return "done", nil
+		})
+		if err != nil || result != "done" {
+			t.Errorf("expected only the result of the function to be returned, got %s %v", result, err)
+		}
+	})
+}
@@ -0,0 +1,19 @@
+module github.com/wepala/langfuse-go/langfuselangchain
+
+// langchaingo v0.1.14 requires go 1.24.4 so this module can't use the go version of the root module
+go 1.24.4
+
+require (
+	github.com/segmentio/ksuid v1.0.4
+	github.com/tmc/langchaingo v0.1.14
+	github.com/wepala/langfuse-go v0.2.0
+)
+
+require (
+	github.com/dlclark/regexp2 v1.10.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/pkoukk/tiktoken-go v0.1.6 // indirect
+)
+
+//the root module is replaced for development in this repository. Consumers get the release required above
+replace github.com/wepala/langfuse-go => ../
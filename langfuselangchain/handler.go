@@ -0,0 +1,388 @@
+// Package langfuselangchain records github.com/tmc/langchaingo runs in langfuse. Chains are recorded as spans, llm
+// calls as generations, tools as tool spans and retriever calls as events
+package langfuselangchain
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+const (
+	kindChain = "chain"
+	kindLLM   = "llm"
+	kindTool  = "tool"
+)
+
+var _ callbacks.Handler = (*Handler)(nil)
+
+// Handler is a langchaingo callbacks handler. langchaingo doesn't give callbacks a run id so each call is given one
+// with ContextWithRun and callbacks without a run are ignored. Steps of a run that happen at the same time e.g. tools
+// called in parallel may be nested under each other
+type Handler struct {
+	mu   sync.Mutex
+	runs map[string]*run
+}
+
+type runKey struct{}
+
+// ContextWithRun returns the context for one call of a chain, agent or llm that records it under the parent. Calls
+// made at the same time under the same parent need their own context so their steps aren't mixed up
+func ContextWithRun(ctxt context.Context, parent langfuse.Parent) context.Context {
+	return context.WithValue(langfuse.ContextWithParent(ctxt, parent), runKey{}, ksuid.New().String())
+}
+
+type run struct {
+	id         string
+	parent     langfuse.Parent
+	stack      []*step
+	tool       schema.AgentAction
+	retrievals map[string]time.Time
+}
+
+type step struct {
+	kind       string
+	parent     langfuse.Parent
+	generation *langfuse.Generation
+	streaming  bool
+	end        func(output interface{}) error
+	fail       func(err error) error
+}
+
+func NewHandler() *Handler {
+	return &Handler{runs: make(map[string]*run)}
+}
+
+// run returns the run in the context. The handler must be locked
+func (h *Handler) run(ctxt context.Context) *run {
+	id, _ := ctxt.Value(runKey{}).(string)
+	parent, ok := langfuse.ParentFromContext(ctxt)
+	if id == "" || !ok {
+		return nil
+	}
+	r, ok := h.runs[id]
+	if !ok {
+		r = &run{id: id, parent: parent, retrievals: make(map[string]time.Time)}
+		h.runs[id] = r
+	}
+	return r
+}
+
+// release forgets the run once nothing in it is open and no agent action is waiting for its tool. The handler must be
+// locked
+func (h *Handler) release(r *run) {
+	if len(r.stack) > 0 || len(r.retrievals) > 0 || r.tool.Tool != "" {
+		return
+	}
+	delete(h.runs, r.id)
+}
+
+type toolParent interface {
+	Tool(opts *langfuse.Tool) (*langfuse.Tool, error)
+}
+
+type eventParent interface {
+	Event(opts *langfuse.Event) (*langfuse.Event, error)
+}
+
+// event records an event under the current observation
+func (r *run) event(opts *langfuse.Event) {
+	if parent, ok := r.current().(eventParent); ok {
+		_, _ = parent.Event(opts)
+	}
+}
+
+// current is the observation new steps are nested under
+func (r *run) current() langfuse.Parent {
+	if len(r.stack) == 0 {
+		return r.parent
+	}
+	return r.stack[len(r.stack)-1].parent
+}
+
+// pop removes the latest step of the kind
+func (r *run) pop(kind string) *step {
+	for i := len(r.stack) - 1; i >= 0; i-- {
+		if r.stack[i].kind == kind {
+			s := r.stack[i]
+			r.stack = append(r.stack[:i], r.stack[i+1:]...)
+			return s
+		}
+	}
+	return nil
+}
+
+func (h *Handler) start(ctxt context.Context, kind string, create func(r *run) (*step, error)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := h.run(ctxt)
+	if r == nil {
+		return
+	}
+	s, err := create(r)
+	if err != nil {
+		h.release(r)
+		return
+	}
+	s.kind = kind
+	r.stack = append(r.stack, s)
+}
+
+func (h *Handler) finish(ctxt context.Context, kind string, output interface{}, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := h.run(ctxt)
+	if r == nil {
+		return
+	}
+	s := r.pop(kind)
+	h.release(r)
+	if s == nil {
+		return
+	}
+	if err != nil {
+		_ = s.fail(err)
+		return
+	}
+	_ = s.end(output)
+}
+
+func (h *Handler) HandleChainStart(ctxt context.Context, inputs map[string]any) {
+	h.start(ctxt, kindChain, func(r *run) (*step, error) {
+		span, err := r.current().Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{Name: "chain", Input: inputs}})
+		if err != nil {
+			return nil, err
+		}
+		return &step{parent: span, end: span.EndWithOutput, fail: span.EndWithError}, nil
+	})
+}
+
+func (h *Handler) HandleChainEnd(ctxt context.Context, outputs map[string]any) {
+	h.finish(ctxt, kindChain, outputs, nil)
+}
+
+func (h *Handler) HandleChainError(ctxt context.Context, err error) {
+	h.finish(ctxt, kindChain, nil, err)
+}
+
+func (h *Handler) HandleLLMStart(ctxt context.Context, prompts []string) {
+	h.startGeneration(ctxt, prompts)
+}
+
+func (h *Handler) HandleLLMGenerateContentStart(ctxt context.Context, ms []llms.MessageContent) {
+	messages := make([]langfuse.ChatMessage, len(ms))
+	for i, m := range ms {
+		messages[i] = message(m)
+	}
+	h.startGeneration(ctxt, messages)
+}
+
+func (h *Handler) startGeneration(ctxt context.Context, input interface{}) {
+	h.start(ctxt, kindLLM, func(r *run) (*step, error) {
+		generation, err := r.current().Generation(&langfuse.Generation{BasicObservation: langfuse.BasicObservation{Name: "llm", Input: input}})
+		if err != nil {
+			return nil, err
+		}
+		return &step{
+			parent:     generation,
+			generation: generation,
+			end:        generation.EndWithOutput,
+			fail:       generation.EndWithError,
+		}, nil
+	})
+}
+
+func (h *Handler) HandleLLMGenerateContentEnd(ctxt context.Context, res *llms.ContentResponse) {
+	var output interface{}
+	if res != nil && len(res.Choices) > 0 {
+		choice := res.Choices[0]
+		var toolCalls []langfuse.ToolCall
+		for _, call := range choice.ToolCalls {
+			if call.FunctionCall != nil {
+				toolCalls = append(toolCalls, langfuse.ToolCall{ID: call.ID, Name: call.FunctionCall.Name, Arguments: call.FunctionCall.Arguments})
+			}
+		}
+		output = langfuse.AssistantMessage(choice.Content, toolCalls...)
+		h.mu.Lock()
+		if r := h.run(ctxt); r != nil {
+			for i := len(r.stack) - 1; i >= 0; i-- {
+				if r.stack[i].generation != nil {
+					r.stack[i].generation.SetUsage(usage(choice.GenerationInfo))
+					break
+				}
+			}
+		}
+		h.mu.Unlock()
+	}
+	h.finish(ctxt, kindLLM, output, nil)
+}
+
+func (h *Handler) HandleLLMError(ctxt context.Context, err error) {
+	h.finish(ctxt, kindLLM, nil, err)
+}
+
+// HandleStreamingFunc records when the first chunk of the response arrives
+func (h *Handler) HandleStreamingFunc(ctxt context.Context, chunk []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := h.run(ctxt)
+	if r == nil {
+		return
+	}
+	defer h.release(r)
+	for i := len(r.stack) - 1; i >= 0; i-- {
+		if s := r.stack[i]; s.generation != nil {
+			if !s.streaming {
+				s.streaming = true
+				s.generation.SetCompletionStartTime(time.Now())
+			}
+			return
+		}
+	}
+}
+
+func (h *Handler) HandleText(ctxt context.Context, text string) {}
+
+func (h *Handler) HandleToolStart(ctxt context.Context, input string) {
+	h.start(ctxt, kindTool, func(r *run) (*step, error) {
+		opts := &langfuse.Tool{Span: langfuse.Span{BasicObservation: langfuse.BasicObservation{Name: "tool"}}, Arguments: input}
+		//agents report the action before calling the tool
+		if r.tool.Tool != "" {
+			opts.Name = r.tool.Tool
+			opts.ToolCallID = r.tool.ToolID
+			r.tool = schema.AgentAction{}
+		}
+		parent, ok := r.current().(toolParent)
+		if !ok {
+			return nil, errors.New("parent can't have tools")
+		}
+		tool, err := parent.Tool(opts)
+		if err != nil {
+			return nil, err
+		}
+		return &step{parent: &tool.Span, end: tool.EndWithOutput, fail: tool.EndWithError}, nil
+	})
+}
+
+func (h *Handler) HandleToolEnd(ctxt context.Context, output string) {
+	h.finish(ctxt, kindTool, output, nil)
+}
+
+func (h *Handler) HandleToolError(ctxt context.Context, err error) {
+	h.finish(ctxt, kindTool, nil, err)
+}
+
+func (h *Handler) HandleAgentAction(ctxt context.Context, action schema.AgentAction) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := h.run(ctxt)
+	if r == nil {
+		return
+	}
+	defer h.release(r)
+	r.tool = action
+	r.event(&langfuse.Event{BasicObservation: langfuse.BasicObservation{
+		Name:     "agent action",
+		Input:    action.ToolInput,
+		Metadata: map[string]interface{}{"tool": action.Tool, "log": action.Log},
+	}})
+}
+
+func (h *Handler) HandleAgentFinish(ctxt context.Context, finish schema.AgentFinish) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := h.run(ctxt)
+	if r == nil {
+		return
+	}
+	defer h.release(r)
+	r.event(&langfuse.Event{BasicObservation: langfuse.BasicObservation{
+		Name:     "agent finish",
+		Output:   finish.ReturnValues,
+		Metadata: map[string]interface{}{"log": finish.Log},
+	}})
+}
+
+func (h *Handler) HandleRetrieverStart(ctxt context.Context, query string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if r := h.run(ctxt); r != nil {
+		r.retrievals[query] = time.Now()
+	}
+}
+
+func (h *Handler) HandleRetrieverEnd(ctxt context.Context, query string, documents []schema.Document) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := h.run(ctxt)
+	if r == nil {
+		return
+	}
+	start, ok := r.retrievals[query]
+	if !ok {
+		start = time.Now()
+	}
+	delete(r.retrievals, query)
+	h.release(r)
+	output := make([]map[string]interface{}, len(documents))
+	for i, document := range documents {
+		output[i] = map[string]interface{}{"pageContent": document.PageContent, "metadata": document.Metadata, "score": document.Score}
+	}
+	r.event(&langfuse.Event{
+		BasicObservation: langfuse.BasicObservation{Name: "retriever", Input: query, Output: output},
+		StartTime:        start,
+	})
+}
+
+func usage(info map[string]any) map[string]interface{} {
+	usage := map[string]interface{}{}
+	for key, name := range map[string]string{"PromptTokens": "promptTokens", "CompletionTokens": "completionTokens", "TotalTokens": "totalTokens"} {
+		if value, ok := info[key]; ok {
+			usage[name] = value
+		}
+	}
+	if len(usage) == 0 {
+		return nil
+	}
+	return usage
+}
+
+func message(m llms.MessageContent) langfuse.ChatMessage {
+	message := langfuse.ChatMessage{Role: role(m.Role)}
+	for _, part := range m.Parts {
+		switch p := part.(type) {
+		case llms.TextContent:
+			message.Content = append(message.Content, langfuse.TextPart(p.Text))
+		case llms.ImageURLContent:
+			message.Content = append(message.Content, langfuse.ContentPart{Type: langfuse.CONTENT_IMAGE_URL, ImageURL: &langfuse.ImageURL{URL: p.URL, Detail: p.Detail}})
+		case llms.BinaryContent:
+			message.Content = append(message.Content, langfuse.ContentPartFromDataURI(p.String()))
+		case llms.ToolCall:
+			if p.FunctionCall != nil {
+				message.ToolCalls = append(message.ToolCalls, langfuse.ToolCall{ID: p.ID, Name: p.FunctionCall.Name, Arguments: p.FunctionCall.Arguments})
+			}
+		case llms.ToolCallResponse:
+			message.ToolCallID = p.ToolCallID
+			message.Name = p.Name
+			message.Content = append(message.Content, langfuse.TextPart(p.Content))
+		}
+	}
+	return message
+}
+
+func role(role llms.ChatMessageType) string {
+	switch role {
+	case llms.ChatMessageTypeHuman:
+		return langfuse.ROLE_USER
+	case llms.ChatMessageTypeAI:
+		return langfuse.ROLE_ASSISTANT
+	}
+	return string(role)
+}
@@ -0,0 +1,38 @@
+package langfuselangchain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+func TestHandler_release(t *testing.T) {
+	t.Run("should not keep runs that callbacks only read", func(t *testing.T) {
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: langfuse.NewMemoryEventManager()})
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		handler := NewHandler()
+		ctxt := ContextWithRun(context.TODO(), trace)
+		handler.HandleStreamingFunc(ctxt, []byte("hel"))
+		handler.HandleAgentFinish(ctxt, schema.AgentFinish{Log: "done"})
+		if len(handler.runs) != 0 {
+			t.Errorf("expected no runs to be kept, got %d", len(handler.runs))
+		}
+	})
+	t.Run("should keep the run until the tool of the agent action ends", func(t *testing.T) {
+		sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: langfuse.NewMemoryEventManager()})
+		trace, _ := sdk.Trace(context.TODO(), nil)
+		handler := NewHandler()
+		ctxt := ContextWithRun(context.TODO(), trace)
+		handler.HandleAgentAction(ctxt, schema.AgentAction{Tool: "search"})
+		if len(handler.runs) != 1 {
+			t.Fatalf("expected the run to be kept for the tool, got %d", len(handler.runs))
+		}
+		handler.HandleToolStart(ctxt, "query")
+		handler.HandleToolEnd(ctxt, "result")
+		if len(handler.runs) != 0 {
+			t.Errorf("expected the run to be released, got %d", len(handler.runs))
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package langfuselangchain_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/wepala/langfuse-go/langfuse"
+	"github.com/wepala/langfuse-go/langfuselangchain"
+)
+
+// recorder keeps a json snapshot of every event that's enqueued
+type recorder struct {
+	mu     sync.Mutex
+	types  []string
+	events []map[string]interface{}
+}
+
+func (r *recorder) Enqueue(id string, eventType string, event interface{}) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var snapshot map[string]interface{}
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types = append(r.types, eventType)
+	r.events = append(r.events, snapshot)
+	return nil
+}
+
+func (r *recorder) Flush(ctxt context.Context) {}
+
+func setup() (*langfuselangchain.Handler, context.Context, *recorder) {
+	events := &recorder{}
+	sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: events})
+	trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
+	return langfuselangchain.NewHandler(), langfuselangchain.ContextWithRun(context.TODO(), trace), events
+}
+
+func TestHandler_Chain(t *testing.T) {
+	t.Run("should nest the llm call in the chain span", func(t *testing.T) {
+		handler, ctxt, events := setup()
+		handler.HandleChainStart(ctxt, map[string]any{"question": "hi"})
+		handler.HandleLLMGenerateContentStart(ctxt, []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")})
+		handler.HandleStreamingFunc(ctxt, []byte("hel"))
+		handler.HandleLLMGenerateContentEnd(ctxt, &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+			Content:        "hello",
+			GenerationInfo: map[string]any{"PromptTokens": 3, "CompletionTokens": 1, "TotalTokens": 4},
+		}}})
+		handler.HandleChainEnd(ctxt, map[string]any{"text": "hello"})
+
+		expected := []string{langfuse.TRACE_CREATE, langfuse.SPAN_CREATE, langfuse.GENERATION_CREATE, langfuse.GENERATION_UPDATE, langfuse.SPAN_UPDATE}
+		if len(events.types) != len(expected) {
+			t.Fatalf("expected events %v, got %v", expected, events.types)
+		}
+		for i, eventType := range expected {
+			if events.types[i] != eventType {
+				t.Errorf("expected event %d to be %s, got %s", i, eventType, events.types[i])
+			}
+		}
+		span, generation := events.events[1], events.events[3]
+		if generation["parentObservationId"] != span["id"] {
+			t.Errorf("expected the generation to be nested in the chain span")
+		}
+		input := events.events[2]["input"].([]interface{})[0].(map[string]interface{})
+		if input["role"] != langfuse.ROLE_USER || input["content"] != "hi" {
+			t.Errorf("expected the input to be recorded as chat messages, got %v", input)
+		}
+		output := generation["output"].(map[string]interface{})
+		if output["role"] != langfuse.ROLE_ASSISTANT || output["content"] != "hello" {
+			t.Errorf("expected the output to be recorded as an assistant message, got %v", output)
+		}
+		usage := generation["usage"].(map[string]interface{})
+		if usage["promptTokens"] != float64(3) || usage["totalTokens"] != float64(4) {
+			t.Errorf("expected usage to be recorded, got %v", usage)
+		}
+		if generation["completionStartTime"] == nil {
+			t.Errorf("expected the time of the first chunk to be recorded")
+		}
+		if events.events[4]["endTime"] == nil {
+			t.Errorf("expected the chain span to be ended")
+		}
+	})
+	t.Run("should record chain errors", func(t *testing.T) {
+		handler, ctxt, events := setup()
+		handler.HandleChainStart(ctxt, map[string]any{})
+		handler.HandleChainError(ctxt, errors.New("failed"))
+		span := events.events[2]
+		if span["level"] != langfuse.LEVEL_ERROR || span["statusMessage"] != "failed" {
+			t.Errorf("expected the span to be ended with the error, got %v", span)
+		}
+	})
+	t.Run("should ignore callbacks without a run in the context", func(t *testing.T) {
+		handler, ctxt, events := setup()
+		parent, _ := langfuse.ParentFromContext(ctxt)
+		for _, ctxt := range []context.Context{context.TODO(), langfuse.ContextWithParent(context.TODO(), parent)} {
+			handler.HandleChainStart(ctxt, map[string]any{})
+			handler.HandleChainEnd(ctxt, map[string]any{})
+		}
+		if len(events.types) != 1 {
+			t.Errorf("expected only the trace to be recorded, got %v", events.types)
+		}
+	})
+	t.Run("should keep runs under the same parent apart", func(t *testing.T) {
+		handler, ctxt, events := setup()
+		parent, _ := langfuse.ParentFromContext(ctxt)
+		other := langfuselangchain.ContextWithRun(context.TODO(), parent)
+		handler.HandleChainStart(ctxt, map[string]any{})
+		handler.HandleChainStart(other, map[string]any{})
+		handler.HandleLLMStart(other, []string{"hi"})
+		generation := events.events[3]
+		if generation["parentObservationId"] != events.events[2]["id"] {
+			t.Errorf("expected the llm call to be nested in the chain of its own run")
+		}
+	})
+}
+
+func TestHandler_Agent(t *testing.T) {
+	t.Run("should record the tool named in the agent action", func(t *testing.T) {
+		handler, ctxt, events := setup()
+		handler.HandleAgentAction(ctxt, schema.AgentAction{Tool: "calculator", ToolInput: "1+1", ToolID: "call-1"})
+		handler.HandleToolStart(ctxt, "1+1")
+		handler.HandleToolEnd(ctxt, "2")
+		handler.HandleAgentFinish(ctxt, schema.AgentFinish{ReturnValues: map[string]any{"output": "2"}})
+
+		if len(events.types) != 5 {
+			t.Fatalf("expected %d events, got %v", 5, events.types)
+		}
+		tool := events.events[3]
+		if tool["name"] != "calculator" || tool["output"] != "2" || tool["endTime"] == nil {
+			t.Errorf("expected the tool span to be ended with the output, got %v", tool)
+		}
+		metadata := tool["metadata"].(map[string]interface{})
+		if metadata[langfuse.METADATA_TYPE] != langfuse.OBSERVATION_TOOL || metadata[langfuse.METADATA_TOOL_CALL_ID] != "call-1" {
+			t.Errorf("expected the tool metadata to be set, got %v", metadata)
+		}
+		if events.events[4]["name"] != "agent finish" {
+			t.Errorf("expected the agent finish to be recorded, got %v", events.events[4])
+		}
+	})
+}
+
+func TestHandler_Retriever(t *testing.T) {
+	t.Run("should record the query and documents", func(t *testing.T) {
+		handler, ctxt, events := setup()
+		handler.HandleRetrieverStart(ctxt, "weather")
+		handler.HandleRetrieverEnd(ctxt, "weather", []schema.Document{{PageContent: "sunny", Score: 0.9}})
+		if len(events.types) != 2 || events.types[1] != langfuse.EVENT_CREATE {
+			t.Fatalf("expected an event to be recorded, got %v", events.types)
+		}
+		event := events.events[1]
+		documents := event["output"].([]interface{})
+		if event["input"] != "weather" || len(documents) != 1 || documents[0].(map[string]interface{})["pageContent"] != "sunny" {
+			t.Errorf("expected the query and documents to be recorded, got %v", event)
+		}
+	})
+}
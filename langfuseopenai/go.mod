@@ -0,0 +1,13 @@
+module github.com/wepala/langfuse-go/langfuseopenai
+
+go 1.18
+
+require (
+	github.com/sashabaranov/go-openai v1.43.0
+	github.com/wepala/langfuse-go v0.2.0
+)
+
+require github.com/segmentio/ksuid v1.0.4 // indirect
+
+//the root module is replaced for development in this repository. Consumers get the release required above
+replace github.com/wepala/langfuse-go => ../
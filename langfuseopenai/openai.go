@@ -0,0 +1,190 @@
+// Package langfuseopenai traces calls made with the github.com/sashabaranov/go-openai client. Calls are recorded as
+// generations under the observation found in the context with langfuse.ParentFromContext. Calls made without a
+// parent in the context aren't traced
+package langfuseopenai
+
+import (
+	"context"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+// Client wraps an openai client so its calls are traced
+type Client struct {
+	client *openai.Client
+}
+
+func New(client *openai.Client) *Client {
+	return &Client{client: client}
+}
+
+// Client returns the wrapped client for calls that aren't traced
+func (c *Client) Client() *openai.Client {
+	return c.client
+}
+
+func (c *Client) CreateChatCompletion(ctxt context.Context, request openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	generation, err := startGeneration(ctxt, "chat-completion", request)
+	if err != nil || generation == nil {
+		return c.client.CreateChatCompletion(ctxt, request)
+	}
+
+	response, err := c.client.CreateChatCompletion(ctxt, request)
+	if err != nil {
+		_ = generation.EndWithError(err)
+		return response, err
+	}
+	if response.Model != "" {
+		generation.SetModel(response.Model)
+	}
+	generation.SetUsage(usage(response.Usage))
+	if len(response.Choices) > 0 {
+		generation.SetChatOutput(message(response.Choices[0].Message))
+	}
+	_ = generation.End()
+	return response, nil
+}
+
+func (c *Client) CreateChatCompletionStream(ctxt context.Context, request openai.ChatCompletionRequest) (*ChatCompletionStream, error) {
+	generation, err := startGeneration(ctxt, "chat-completion", request)
+	if err != nil {
+		generation = nil
+	}
+
+	stream, err := c.client.CreateChatCompletionStream(ctxt, request)
+	if err != nil {
+		if generation != nil {
+			_ = generation.EndWithError(err)
+		}
+		return nil, err
+	}
+	return &ChatCompletionStream{ChatCompletionStream: stream, generation: generation}, nil
+}
+
+func (c *Client) CreateEmbeddings(ctxt context.Context, conv openai.EmbeddingRequestConverter) (openai.EmbeddingResponse, error) {
+	parent, ok := langfuse.ParentFromContext(ctxt)
+	if !ok {
+		return c.client.CreateEmbeddings(ctxt, conv)
+	}
+	request := conv.Convert()
+	parameters := map[string]interface{}{}
+	if request.Dimensions != 0 {
+		parameters["dimensions"] = request.Dimensions
+	}
+	generation, err := parent.Generation(&langfuse.Generation{
+		BasicObservation: langfuse.BasicObservation{Name: "embedding", Input: request.Input},
+		Model:            string(request.Model),
+		ModelParameters:  parameters,
+	})
+	if err != nil {
+		return c.client.CreateEmbeddings(ctxt, conv)
+	}
+
+	response, err := c.client.CreateEmbeddings(ctxt, conv)
+	if err != nil {
+		_ = generation.EndWithError(err)
+		return response, err
+	}
+	generation.SetUsage(map[string]interface{}{"input": response.Usage.PromptTokens, "total": response.Usage.TotalTokens, "unit": "TOKENS"})
+	output := map[string]interface{}{"embeddings": len(response.Data)}
+	if len(response.Data) > 0 {
+		output["dimensions"] = len(response.Data[0].Embedding)
+	}
+	_ = generation.EndWithOutput(output)
+	return response, nil
+}
+
+func startGeneration(ctxt context.Context, name string, request openai.ChatCompletionRequest) (*langfuse.Generation, error) {
+	parent, ok := langfuse.ParentFromContext(ctxt)
+	if !ok {
+		return nil, nil
+	}
+	generation, err := parent.Generation(&langfuse.Generation{
+		BasicObservation: langfuse.BasicObservation{Name: name},
+		Model:            request.Model,
+		ModelParameters:  parameters(request),
+		StartTime:        time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]langfuse.ChatMessage, len(request.Messages))
+	for i, m := range request.Messages {
+		messages[i] = message(m)
+	}
+	var tools []langfuse.ToolDefinition
+	for _, tool := range request.Tools {
+		if tool.Function != nil {
+			tools = append(tools, langfuse.ToolDefinition{Name: tool.Function.Name, Description: tool.Function.Description, Parameters: tool.Function.Parameters})
+		}
+	}
+	generation.SetChatInput(messages, tools...)
+	return generation, nil
+}
+
+func parameters(request openai.ChatCompletionRequest) map[string]interface{} {
+	parameters := map[string]interface{}{}
+	if request.Temperature != 0 {
+		parameters["temperature"] = request.Temperature
+	}
+	if request.TopP != 0 {
+		parameters["top_p"] = request.TopP
+	}
+	if request.MaxTokens != 0 {
+		parameters["max_tokens"] = request.MaxTokens
+	}
+	if request.MaxCompletionTokens != 0 {
+		parameters["max_completion_tokens"] = request.MaxCompletionTokens
+	}
+	if request.PresencePenalty != 0 {
+		parameters["presence_penalty"] = request.PresencePenalty
+	}
+	if request.FrequencyPenalty != 0 {
+		parameters["frequency_penalty"] = request.FrequencyPenalty
+	}
+	if request.N != 0 {
+		parameters["n"] = request.N
+	}
+	if request.Seed != nil {
+		parameters["seed"] = *request.Seed
+	}
+	if len(request.Stop) > 0 {
+		parameters["stop"] = request.Stop
+	}
+	if request.ReasoningEffort != "" {
+		parameters["reasoning_effort"] = request.ReasoningEffort
+	}
+	return parameters
+}
+
+func usage(u openai.Usage) map[string]interface{} {
+	return map[string]interface{}{
+		"promptTokens":     u.PromptTokens,
+		"completionTokens": u.CompletionTokens,
+		"totalTokens":      u.TotalTokens,
+	}
+}
+
+func message(m openai.ChatCompletionMessage) langfuse.ChatMessage {
+	message := langfuse.ChatMessage{Role: m.Role, Name: m.Name, ToolCallID: m.ToolCallID}
+	if m.Content != "" {
+		message.Content = []langfuse.ContentPart{langfuse.TextPart(m.Content)}
+	}
+	for _, part := range m.MultiContent {
+		switch {
+		case part.Type == openai.ChatMessagePartTypeImageURL && part.ImageURL != nil:
+			message.Content = append(message.Content, langfuse.ContentPart{
+				Type:     langfuse.CONTENT_IMAGE_URL,
+				ImageURL: &langfuse.ImageURL{URL: part.ImageURL.URL, Detail: string(part.ImageURL.Detail)},
+			})
+		default:
+			message.Content = append(message.Content, langfuse.TextPart(part.Text))
+		}
+	}
+	for _, call := range m.ToolCalls {
+		message.ToolCalls = append(message.ToolCalls, langfuse.ToolCall{ID: call.ID, Name: call.Function.Name, Arguments: call.Function.Arguments})
+	}
+	return message
+}
@@ -0,0 +1,222 @@
+package langfuseopenai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/wepala/langfuse-go/langfuse"
+	"github.com/wepala/langfuse-go/langfuseopenai"
+)
+
+// recorder keeps a json snapshot of every event that's enqueued
+type recorder struct {
+	mu     sync.Mutex
+	types  []string
+	events []map[string]interface{}
+}
+
+func (r *recorder) Enqueue(id string, eventType string, event interface{}) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var snapshot map[string]interface{}
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types = append(r.types, eventType)
+	r.events = append(r.events, snapshot)
+	return nil
+}
+
+func (r *recorder) Flush(ctxt context.Context) {}
+
+func setup(t *testing.T, handler http.HandlerFunc) (*langfuseopenai.Client, context.Context, *recorder) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	config := openai.DefaultConfig("test-key")
+	config.BaseURL = server.URL + "/v1"
+	events := &recorder{}
+	sdk := langfuse.New(context.TODO(), langfuse.Options{EventManager: events})
+	trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{ID: "trace-id"}})
+	return langfuseopenai.New(openai.NewClientWithConfig(config)), langfuse.ContextWithParent(context.TODO(), trace), events
+}
+
+func TestClient_CreateChatCompletion(t *testing.T) {
+	t.Run("should record the completion as a generation", func(t *testing.T) {
+		client, ctxt, events := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/v1/chat/completions" {
+				t.Errorf("unexpected request to %s", r.URL.Path)
+			}
+			_, _ = io.WriteString(w, `{"id":"chatcmpl-1","model":"gpt-4o-2024-08-06","choices":[{"index":0,"message":{"role":"assistant","content":"hello"},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":2,"total_tokens":12}}`)
+		})
+		response, err := client.CreateChatCompletion(ctxt, openai.ChatCompletionRequest{
+			Model:       "gpt-4o",
+			Temperature: 0.5,
+			Messages:    []openai.ChatCompletionMessage{{Role: openai.ChatMessageRoleUser, Content: "hi"}},
+			Tools:       []openai.Tool{{Type: openai.ToolTypeFunction, Function: &openai.FunctionDefinition{Name: "search"}}},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if response.Choices[0].Message.Content != "hello" {
+			t.Errorf("expected the response to be returned")
+		}
+		if len(events.types) != 3 || events.types[1] != langfuse.GENERATION_CREATE || events.types[2] != langfuse.GENERATION_UPDATE {
+			t.Fatalf("expected a generation to be created and ended, got %v", events.types)
+		}
+		generation := events.events[2]
+		if generation["traceId"] != "trace-id" || generation["model"] != "gpt-4o-2024-08-06" || generation["endTime"] == nil {
+			t.Errorf("expected the generation to be ended with the model, got %v", generation)
+		}
+		if generation["modelParameters"].(map[string]interface{})["temperature"] != 0.5 {
+			t.Errorf("expected model parameters to be recorded, got %v", generation["modelParameters"])
+		}
+		input := generation["input"].(map[string]interface{})
+		if input["messages"].([]interface{})[0].(map[string]interface{})["content"] != "hi" || len(input["tools"].([]interface{})) != 1 {
+			t.Errorf("expected messages and tools to be recorded, got %v", input)
+		}
+		if generation["output"].(map[string]interface{})["content"] != "hello" {
+			t.Errorf("expected output to be recorded, got %v", generation["output"])
+		}
+		if generation["usage"].(map[string]interface{})["totalTokens"] != float64(12) {
+			t.Errorf("expected usage to be recorded, got %v", generation["usage"])
+		}
+	})
+	t.Run("should record errors", func(t *testing.T) {
+		client, ctxt, events := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = io.WriteString(w, `{"error":{"message":"rate limited","type":"rate_limit"}}`)
+		})
+		_, err := client.CreateChatCompletion(ctxt, openai.ChatCompletionRequest{Model: "gpt-4o", Messages: []openai.ChatCompletionMessage{{Role: "user", Content: "hi"}}})
+		if err == nil {
+			t.Fatalf("expected an error to be returned")
+		}
+		if generation := events.events[2]; generation["level"] != langfuse.LEVEL_ERROR {
+			t.Errorf("expected the error to be recorded, got %v", generation)
+		}
+	})
+	t.Run("should not trace calls without a parent", func(t *testing.T) {
+		client, _, events := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, `{"choices":[{"message":{"role":"assistant","content":"hello"}}]}`)
+		})
+		if _, err := client.CreateChatCompletion(context.TODO(), openai.ChatCompletionRequest{Model: "gpt-4o"}); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if len(events.types) != 1 {
+			t.Errorf("expected only the trace to be recorded, got %v", events.types)
+		}
+	})
+}
+
+func TestClient_CreateChatCompletionStream(t *testing.T) {
+	t.Run("should record the streamed completion with the time to first token", func(t *testing.T) {
+		client, ctxt, events := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			chunks := []string{
+				`{"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"hel"}}]}`,
+				`{"model":"gpt-4o","choices":[{"index":0,"delta":{"content":"lo"}}]}`,
+				`{"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call-1","type":"function","function":{"name":"search","arguments":"{\"q\":"}}]}}]}`,
+				`{"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"go\"}"}}]}}]}`,
+				`{"model":"gpt-4o","choices":[],"usage":{"prompt_tokens":5,"completion_tokens":3,"total_tokens":8}}`,
+			}
+			for _, chunk := range chunks {
+				_, _ = fmt.Fprintf(w, "data: %s\n\n", chunk)
+			}
+			_, _ = io.WriteString(w, "data: [DONE]\n\n")
+		})
+		stream, err := client.CreateChatCompletionStream(ctxt, openai.ChatCompletionRequest{Model: "gpt-4o", Messages: []openai.ChatCompletionMessage{{Role: "user", Content: "hi"}}})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		for {
+			if _, err = stream.Recv(); err != nil {
+				break
+			}
+		}
+		if !errors.Is(err, io.EOF) {
+			t.Fatalf("expected the stream to end, got %s", err)
+		}
+		_ = stream.Close()
+		if len(events.types) != 3 {
+			t.Fatalf("expected the generation to be ended once, got %v", events.types)
+		}
+		generation := events.events[2]
+		if generation["completionStartTime"] == "0001-01-01T00:00:00Z" {
+			t.Errorf("expected the completion start time to be recorded")
+		}
+		output := generation["output"].(map[string]interface{})
+		if output["content"] != "hello" {
+			t.Errorf("expected the streamed content to be recorded, got %v", output)
+		}
+		call := output["tool_calls"].([]interface{})[0].(map[string]interface{})["function"].(map[string]interface{})
+		if call["name"] != "search" || call["arguments"] != `{"q":"go"}` {
+			t.Errorf("expected the streamed tool call to be recorded, got %v", call)
+		}
+		if generation["usage"].(map[string]interface{})["totalTokens"] != float64(8) {
+			t.Errorf("expected usage to be recorded, got %v", generation["usage"])
+		}
+	})
+	t.Run("should merge tool call pieces without an index into the last call", func(t *testing.T) {
+		client, ctxt, events := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			chunks := []string{
+				`{"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","tool_calls":[{"id":"call-1","type":"function","function":{"name":"search","arguments":"{\"q\":"}}]}}]}`,
+				`{"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"function":{"arguments":"\"go\"}"}}]}}]}`,
+				`{"model":"gpt-4o","choices":[{"index":0,"delta":{"tool_calls":[{"id":"call-2","type":"function","function":{"name":"fetch","arguments":"{}"}}]}}]}`,
+			}
+			for _, chunk := range chunks {
+				_, _ = fmt.Fprintf(w, "data: %s\n\n", chunk)
+			}
+			_, _ = io.WriteString(w, "data: [DONE]\n\n")
+		})
+		stream, err := client.CreateChatCompletionStream(ctxt, openai.ChatCompletionRequest{Model: "gpt-4o", Messages: []openai.ChatCompletionMessage{{Role: "user", Content: "hi"}}})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		for {
+			if _, err = stream.Recv(); err != nil {
+				break
+			}
+		}
+		_ = stream.Close()
+		calls := events.events[len(events.events)-1]["output"].(map[string]interface{})["tool_calls"].([]interface{})
+		if len(calls) != 2 {
+			t.Fatalf("expected %d tool calls, got %v", 2, calls)
+		}
+		first := calls[0].(map[string]interface{})["function"].(map[string]interface{})
+		second := calls[1].(map[string]interface{})["function"].(map[string]interface{})
+		if first["name"] != "search" || first["arguments"] != `{"q":"go"}` || second["name"] != "fetch" {
+			t.Errorf("expected the pieces to be merged into their calls, got %v and %v", first, second)
+		}
+	})
+}
+
+func TestClient_CreateEmbeddings(t *testing.T) {
+	t.Run("should record the embedding as a generation", func(t *testing.T) {
+		client, ctxt, events := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, `{"object":"list","model":"text-embedding-3-small","data":[{"object":"embedding","index":0,"embedding":[0.1,0.2,0.3]}],"usage":{"prompt_tokens":4,"total_tokens":4}}`)
+		})
+		_, err := client.CreateEmbeddings(ctxt, openai.EmbeddingRequestStrings{Input: []string{"hello"}, Model: openai.SmallEmbedding3})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		generation := events.events[2]
+		if generation["model"] != string(openai.SmallEmbedding3) || generation["output"].(map[string]interface{})["dimensions"] != float64(3) {
+			t.Errorf("expected the embedding to be recorded, got %v", generation)
+		}
+		if generation["usage"].(map[string]interface{})["input"] != float64(4) {
+			t.Errorf("expected usage to be recorded, got %v", generation["usage"])
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package langfuseopenai
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+// ChatCompletionStream records the streamed completion as it's read. The generation ends when the stream is read to
+// the end, fails or is closed. The time of the first chunk is the completion start time
+type ChatCompletionStream struct {
+	*openai.ChatCompletionStream
+	generation *langfuse.Generation
+	started    bool
+	ended      bool
+	role       string
+	content    strings.Builder
+	toolCalls  []langfuse.ToolCall
+	usage      *openai.Usage
+	model      string
+}
+
+func (s *ChatCompletionStream) Recv() (openai.ChatCompletionStreamResponse, error) {
+	response, err := s.ChatCompletionStream.Recv()
+	if s.generation == nil || s.ended {
+		return response, err
+	}
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			s.end()
+		} else {
+			s.ended = true
+			_ = s.generation.EndWithError(err)
+		}
+		return response, err
+	}
+
+	if !s.started {
+		s.started = true
+		s.generation.SetCompletionStartTime(time.Now())
+	}
+	if response.Model != "" {
+		s.model = response.Model
+	}
+	if response.Usage != nil {
+		s.usage = response.Usage
+	}
+	if len(response.Choices) > 0 {
+		delta := response.Choices[0].Delta
+		if delta.Role != "" {
+			s.role = delta.Role
+		}
+		s.content.WriteString(delta.Content)
+		for _, call := range delta.ToolCalls {
+			//tool calls are streamed in pieces identified by their index. Pieces without an index continue the last call
+			//unless they start a call with a new id
+			index := len(s.toolCalls) - 1
+			if call.Index != nil {
+				index = *call.Index
+			} else if index < 0 || (call.ID != "" && s.toolCalls[index].ID != "" && call.ID != s.toolCalls[index].ID) {
+				index = len(s.toolCalls)
+			}
+			for len(s.toolCalls) <= index {
+				s.toolCalls = append(s.toolCalls, langfuse.ToolCall{})
+			}
+			if call.ID != "" {
+				s.toolCalls[index].ID = call.ID
+			}
+			s.toolCalls[index].Name += call.Function.Name
+			s.toolCalls[index].Arguments += call.Function.Arguments
+		}
+	}
+	return response, nil
+}
+
+// Close closes the stream and ends the generation with what was read if it hasn't ended
+func (s *ChatCompletionStream) Close() error {
+	if s.generation != nil && !s.ended {
+		s.end()
+	}
+	return s.ChatCompletionStream.Close()
+}
+
+func (s *ChatCompletionStream) end() {
+	s.ended = true
+	if s.role == "" {
+		s.role = langfuse.ROLE_ASSISTANT
+	}
+	if s.model != "" {
+		s.generation.SetModel(s.model)
+	}
+	if s.usage != nil {
+		s.generation.SetUsage(usage(*s.usage))
+	}
+	output := langfuse.AssistantMessage(s.content.String(), s.toolCalls...)
+	output.Role = s.role
+	_ = s.generation.EndWithOutput(output)
+}
@@ -0,0 +1,260 @@
+// Package langfusetest helps test code instrumented with langfuse. Events are recorded in memory and rebuilt into
+// traces with their observation trees so tests can assert on names, nesting, levels and outputs
+package langfusetest
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+// Trace is a trace rebuilt from recorded events. Body is the trace with all its updates applied
+type Trace struct {
+	ID           string
+	Name         string
+	Body         map[string]interface{}
+	Observations []*Observation
+}
+
+// Observation is a span, generation or event rebuilt from recorded events. Body is the observation with all its
+// updates applied
+type Observation struct {
+	ID       string
+	Type     string
+	Name     string
+	Body     map[string]interface{}
+	Parent   *Observation
+	Children []*Observation
+}
+
+// New returns an sdk that records events in memory instead of sending them
+func New(t testing.TB) (*langfuse.LangFuse, *langfuse.MemoryEventManager) {
+	t.Helper()
+	eventManager := langfuse.NewMemoryEventManager()
+	return langfuse.New(context.TODO(), langfuse.Options{EventManager: eventManager}), eventManager
+}
+
+// Traces rebuilds the traces in the events in the order they were created. Observations that aren't nested under
+// another observation are the roots of their trace and children are in the order they were created
+func Traces(events []langfuse.RecordedEvent) []*Trace {
+	var traces []*Trace
+	tracesByID := make(map[string]*Trace)
+	trace := func(id string) *Trace {
+		if trace, ok := tracesByID[id]; ok {
+			return trace
+		}
+		trace := &Trace{ID: id, Body: map[string]interface{}{}}
+		tracesByID[id] = trace
+		traces = append(traces, trace)
+		return trace
+	}
+
+	var observations []*Observation
+	observationsByID := make(map[string]*Observation)
+	for _, event := range events {
+		id, _ := event.Body["id"].(string)
+		if event.Type == langfuse.TRACE_CREATE {
+			trace := trace(id)
+			merge(trace.Body, event.Body)
+			trace.Name, _ = trace.Body["name"].(string)
+			continue
+		}
+		observationType := observationType(event)
+		if observationType == "" {
+			continue
+		}
+		observation, ok := observationsByID[id]
+		if !ok {
+			observation = &Observation{ID: id, Type: observationType, Body: map[string]interface{}{}}
+			observationsByID[id] = observation
+			observations = append(observations, observation)
+		}
+		merge(observation.Body, event.Body)
+		observation.Name, _ = observation.Body["name"].(string)
+	}
+
+	for _, observation := range observations {
+		parentID, _ := observation.Body["parentObservationId"].(string)
+		if parent, ok := observationsByID[parentID]; ok && parent != observation {
+			observation.Parent = parent
+			parent.Children = append(parent.Children, observation)
+			continue
+		}
+		traceID, _ := observation.Body["traceId"].(string)
+		trace := trace(traceID)
+		trace.Observations = append(trace.Observations, observation)
+	}
+	return traces
+}
+
+func observationType(event langfuse.RecordedEvent) string {
+	switch event.Type {
+	case langfuse.SPAN_CREATE, langfuse.SPAN_UPDATE:
+		return "SPAN"
+	case langfuse.GENERATION_CREATE, langfuse.GENERATION_UPDATE:
+		return "GENERATION"
+	case langfuse.EVENT_CREATE:
+		return "EVENT"
+	case langfuse.OBSERVATION_UPDATE:
+		observationType, _ := event.Body["type"].(string)
+		return observationType
+	}
+	return ""
+}
+
+// merge applies an update to a body. Fields missing from the update are kept
+func merge(body map[string]interface{}, update map[string]interface{}) {
+	for key, value := range update {
+		if value != nil {
+			body[key] = value
+		}
+	}
+}
+
+// Observation returns the observation at the path of names starting from the roots of the trace or nil if there
+// isn't one
+func (t *Trace) Observation(path ...string) *Observation {
+	return find(t.Observations, path)
+}
+
+// Observation returns the descendant at the path of names or nil if there isn't one
+func (o *Observation) Observation(path ...string) *Observation {
+	return find(o.Children, path)
+}
+
+func find(observations []*Observation, path []string) *Observation {
+	if len(path) == 0 {
+		return nil
+	}
+	for _, observation := range observations {
+		if observation.Name != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return observation
+		}
+		if found := find(observation.Children, path[1:]); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Level is the level of the observation. Observations without a level are at the DEFAULT level
+func (o *Observation) Level() string {
+	if level, ok := o.Body["level"].(string); ok {
+		return level
+	}
+	return langfuse.LEVEL_DEFAULT
+}
+
+// Output is the output of the observation as it would be sent to the api
+func (o *Observation) Output() interface{} {
+	return o.Body["output"]
+}
+
+// Ended is whether the observation has an end time
+func (o *Observation) Ended() bool {
+	return o.Body["endTime"] != nil
+}
+
+// RequireTrace returns the trace with the name and stops the test if there isn't one
+func RequireTrace(t testing.TB, events []langfuse.RecordedEvent, name string) *Trace {
+	t.Helper()
+	var names []string
+	for _, trace := range Traces(events) {
+		if trace.Name == name {
+			return trace
+		}
+		names = append(names, trace.Name)
+	}
+	t.Fatalf("expected trace %s to be recorded, got %v", name, names)
+	return nil
+}
+
+// RequireObservation returns the observation at the path of names and stops the test if there isn't one
+func RequireObservation(t testing.TB, trace *Trace, path ...string) *Observation {
+	t.Helper()
+	observation := trace.Observation(path...)
+	if observation == nil {
+		t.Fatalf("expected observation %s in trace %s, got\n%s", strings.Join(path, " > "), trace.Name, Format(trace))
+	}
+	return observation
+}
+
+// AssertChildren checks the names of the observation's children in the order they were created
+func AssertChildren(t testing.TB, observation *Observation, names ...string) bool {
+	t.Helper()
+	children := make([]string, len(observation.Children))
+	for i, child := range observation.Children {
+		children[i] = child.Name
+	}
+	if len(names) == 0 && len(children) == 0 {
+		return true
+	}
+	if !reflect.DeepEqual(children, names) {
+		t.Errorf("expected children of %s to be %v, got %v", observation.Name, names, children)
+		return false
+	}
+	return true
+}
+
+// AssertLevel checks the level of the observation
+func AssertLevel(t testing.TB, observation *Observation, level string) bool {
+	t.Helper()
+	if observation.Level() != level {
+		t.Errorf("expected level of %s to be %s, got %s", observation.Name, level, observation.Level())
+		return false
+	}
+	return true
+}
+
+// AssertOutput checks the output of the observation. The expected value is compared after it's converted to json
+// so structs can be compared with the recorded output
+func AssertOutput(t testing.TB, observation *Observation, expected interface{}) bool {
+	t.Helper()
+	data, err := json.Marshal(expected)
+	if err != nil {
+		t.Errorf("expected output to be json, got %s", err)
+		return false
+	}
+	var normalized interface{}
+	if err = json.Unmarshal(data, &normalized); err != nil {
+		t.Errorf("expected output to be json, got %s", err)
+		return false
+	}
+	if !reflect.DeepEqual(observation.Output(), normalized) {
+		t.Errorf("expected output of %s to be %v, got %v", observation.Name, normalized, observation.Output())
+		return false
+	}
+	return true
+}
+
+// AssertEnded checks the observation has an end time
+func AssertEnded(t testing.TB, observation *Observation) bool {
+	t.Helper()
+	if !observation.Ended() {
+		t.Errorf("expected %s to be ended", observation.Name)
+		return false
+	}
+	return true
+}
+
+// Format draws the observation tree of the trace, which is useful in failure messages
+func Format(trace *Trace) string {
+	var builder strings.Builder
+	builder.WriteString(trace.Name + "\n")
+	var write func(observations []*Observation, depth int)
+	write = func(observations []*Observation, depth int) {
+		for _, observation := range observations {
+			builder.WriteString(strings.Repeat("  ", depth+1) + observation.Name + " (" + observation.Type + ", " + observation.Level() + ")\n")
+			write(observation.Children, depth+1)
+		}
+	}
+	write(trace.Observations, 0)
+	return builder.String()
+}
@@ -0,0 +1,79 @@
+package langfusetest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/wepala/langfuse-go/langfuse"
+	"github.com/wepala/langfuse-go/langfusetest"
+)
+
+// fakeT records failures instead of failing the test
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) Helper() {}
+
+func TestTraces(t *testing.T) {
+	t.Run("should rebuild the observation tree with updates applied", func(t *testing.T) {
+		sdk, events := langfusetest.New(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{Name: "chat"}})
+		span, _ := trace.Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{Name: "retrieve"}})
+		generation, _ := span.Generation(&langfuse.Generation{BasicObservation: langfuse.BasicObservation{Name: "answer"}})
+		_ = generation.EndWithOutput("hello")
+		event, _ := span.Event(&langfuse.Event{BasicObservation: langfuse.BasicObservation{Name: "cache miss"}})
+		_ = event.UpdateWithError(errors.New("expired"))
+		_ = span.End()
+
+		recorded := langfusetest.RequireTrace(t, events.Events(), "chat")
+		retrieve := langfusetest.RequireObservation(t, recorded, "retrieve")
+		langfusetest.AssertEnded(t, retrieve)
+		langfusetest.AssertChildren(t, retrieve, "answer", "cache miss")
+		answer := langfusetest.RequireObservation(t, recorded, "retrieve", "answer")
+		if answer.Type != "GENERATION" || answer.Parent != retrieve {
+			t.Errorf("expected answer to be a generation under retrieve, got %s", answer.Type)
+		}
+		langfusetest.AssertOutput(t, answer, "hello")
+		langfusetest.AssertLevel(t, answer, langfuse.LEVEL_DEFAULT)
+		langfusetest.AssertLevel(t, retrieve.Observation("cache miss"), langfuse.LEVEL_ERROR)
+	})
+	t.Run("should put observations of different traces in their own trace", func(t *testing.T) {
+		sdk, events := langfusetest.New(t)
+		first, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{Name: "first"}})
+		second, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{Name: "second"}})
+		_, _ = second.Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{Name: "step"}})
+		_, _ = first.Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{Name: "step"}})
+		traces := langfusetest.Traces(events.Events())
+		if len(traces) != 2 || traces[0].ID != first.ID || len(traces[0].Observations) != 1 || len(traces[1].Observations) != 1 {
+			t.Errorf("expected each trace to have its own span, got %v", traces)
+		}
+	})
+	t.Run("should report the differences", func(t *testing.T) {
+		sdk, events := langfusetest.New(t)
+		trace, _ := sdk.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{Name: "chat"}})
+		span, _ := trace.Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{Name: "retrieve", Output: map[string]interface{}{"count": 1}}})
+		recorded := langfusetest.RequireTrace(t, events.Events(), "chat")
+		observation := recorded.Observation("retrieve")
+		fake := &fakeT{TB: t}
+		if langfusetest.AssertOutput(fake, observation, map[string]interface{}{"count": 2}) {
+			t.Errorf("expected the output assertion to fail")
+		}
+		if langfusetest.AssertEnded(fake, observation) || langfusetest.AssertChildren(fake, observation, "answer") {
+			t.Errorf("expected the ended and children assertions to fail")
+		}
+		if len(fake.errors) != 3 {
+			t.Errorf("expected %d failures, got %v", 3, fake.errors)
+		}
+		if recorded.Observation("retrieve", "answer") != nil || span.ID != observation.ID {
+			t.Errorf("expected only the recorded observations to be found")
+		}
+	})
+}
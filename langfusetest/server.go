@@ -0,0 +1,695 @@
+package langfusetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/ksuid"
+	"github.com/wepala/langfuse-go/api"
+	"github.com/wepala/langfuse-go/langfuse"
+)
+
+const PROJECT_ID = "project-id"
+
+// Server is an in memory Langfuse api for integration tests. Ingested events are validated against the shapes in
+// api.yaml and are reflected by the read endpoints. Requests must use basic auth but any keys are accepted
+type Server struct {
+	*httptest.Server
+	mu           sync.Mutex
+	events       []*api.IngestionEvent
+	rejected     []*api.IngestionError
+	traces       map[string]*record
+	traceIDs     []string
+	observations map[string]*record
+	obsIDs       []string
+	scores       []*api.Score
+	datasets     map[string]*api.Dataset
+	items        map[string]*api.DatasetItem
+	runs         map[string]*api.DatasetRun
+	prompts      map[string][]*api.Prompt
+	//the api prompt type doesn't say whether a version is active
+	active map[*api.Prompt]bool
+}
+
+// record is a trace or observation with all its ingested updates applied
+type record struct {
+	kind      string
+	timestamp time.Time
+	body      map[string]interface{}
+}
+
+// NewServer starts a server. Close it when the test is done
+func NewServer() *Server {
+	s := &Server{
+		traces:       make(map[string]*record),
+		observations: make(map[string]*record),
+		datasets:     make(map[string]*api.Dataset),
+		items:        make(map[string]*api.DatasetItem),
+		runs:         make(map[string]*api.DatasetRun),
+		prompts:      make(map[string][]*api.Prompt),
+		active:       make(map[*api.Prompt]bool),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Options returns sdk options that send events to the server
+func (s *Server) Options() langfuse.Options {
+	return langfuse.Options{
+		Host:       s.URL,
+		PublicKey:  "pk-lf-test",
+		SecretKey:  "sk-lf-test",
+		HttpClient: s.Client(),
+	}
+}
+
+// Events returns the ingested events that were accepted in the order they were received
+func (s *Server) Events() []*api.IngestionEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	events := make([]*api.IngestionEvent, len(s.events))
+	copy(events, s.events)
+	return events
+}
+
+// Rejected returns the errors for the ingested events that didn't match the api
+func (s *Server) Rejected() []*api.IngestionError {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rejected := make([]*api.IngestionError, len(s.rejected))
+	copy(rejected, s.rejected)
+	return rejected
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := r.BasicAuth(); !ok {
+		writeError(w, http.StatusUnauthorized, "basic auth is required")
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/api/public/")
+	if path == r.URL.Path {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	route := r.Method + " " + segments[0]
+	switch {
+	case route == "GET health":
+		writeJSON(w, http.StatusOK, &api.HealthResponse{Version: "test", Status: "OK"})
+	case route == "GET projects":
+		writeJSON(w, http.StatusOK, &api.Projects{Data: []*api.Project{{Id: PROJECT_ID, Name: "test"}}})
+	case route == "POST ingestion":
+		s.ingest(w, r)
+	case route == "GET traces" && len(segments) == 1:
+		s.listTraces(w, r)
+	case route == "GET traces" && len(segments) == 2:
+		s.getTrace(w, segments[1])
+	case route == "GET observations" && len(segments) == 1:
+		s.listObservations(w, r)
+	case route == "GET observations" && len(segments) == 2:
+		s.getObservation(w, segments[1])
+	case route == "GET sessions" && len(segments) == 2:
+		s.getSession(w, segments[1])
+	case route == "GET scores":
+		s.listScores(w, r)
+	case route == "POST scores":
+		s.createScore(w, r)
+	case route == "POST datasets":
+		s.createDataset(w, r)
+	case route == "GET datasets" && len(segments) == 2:
+		s.getDataset(w, segments[1])
+	case route == "GET datasets" && len(segments) == 4 && segments[2] == "runs":
+		s.getRun(w, segments[1], segments[3])
+	case route == "POST dataset-items":
+		s.createItem(w, r)
+	case route == "GET dataset-items" && len(segments) == 2:
+		s.getItem(w, segments[1])
+	case route == "POST dataset-run-items":
+		s.createRunItem(w, r)
+	case route == "GET prompts":
+		s.getPrompt(w, r)
+	case route == "POST prompts":
+		s.createPrompt(w, r)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("%s %s is not supported", r.Method, r.URL.Path))
+	}
+}
+
+func (s *Server) ingest(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Batch []json.RawMessage `json:"batch"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Batch == nil {
+		writeError(w, http.StatusBadRequest, "batch is required")
+		return
+	}
+	response := &api.IngestionResponse{}
+	for _, raw := range request.Batch {
+		id, event, body, timestamp, err := validate(raw)
+		if err != nil {
+			message := err.Error()
+			ingestionError := &api.IngestionError{Id: id, Status: http.StatusBadRequest, Message: &message}
+			s.rejected = append(s.rejected, ingestionError)
+			response.Errors = append(response.Errors, ingestionError)
+			continue
+		}
+		s.events = append(s.events, event)
+		s.apply(event, body, timestamp)
+		response.Successes = append(response.Successes, &api.IngestionSuccess{Id: id, Status: http.StatusCreated})
+	}
+	writeJSON(w, http.StatusMultiStatus, response)
+}
+
+// validate checks the event against the ingestion schema. Observations and traces must also have an id since the
+// server can't return the id it would generate
+func validate(raw json.RawMessage) (string, *api.IngestionEvent, map[string]interface{}, time.Time, error) {
+	var envelope struct {
+		ID        string                 `json:"id"`
+		Type      string                 `json:"type"`
+		Timestamp string                 `json:"timestamp"`
+		Body      map[string]interface{} `json:"body"`
+	}
+	_ = json.Unmarshal(raw, &envelope)
+	if err := langfuse.ValidateIngestionEvent(raw); err != nil {
+		return envelope.ID, nil, nil, time.Time{}, err
+	}
+	if id, _ := envelope.Body["id"].(string); id == "" && envelope.Type != langfuse.SCORE_CREATE && envelope.Type != langfuse.SDK_LOG {
+		return envelope.ID, nil, nil, time.Time{}, fmt.Errorf("invalid %s event %s: body id is required", envelope.Type, envelope.ID)
+	}
+	timestamp, _ := time.Parse(time.RFC3339Nano, envelope.Timestamp)
+	event := &api.IngestionEvent{}
+	if err := json.Unmarshal(raw, event); err != nil {
+		return envelope.ID, nil, nil, time.Time{}, fmt.Errorf("invalid ingestion event: %w", err)
+	}
+	return envelope.ID, event, envelope.Body, timestamp, nil
+}
+
+// apply stores the ingested body. Updates only change the fields they include
+func (s *Server) apply(event *api.IngestionEvent, body map[string]interface{}, timestamp time.Time) {
+	id, _ := body["id"].(string)
+	switch event.Type {
+	case langfuse.TRACE_CREATE:
+		s.traceIDs = upsert(s.traces, s.traceIDs, id, "", timestamp, body)
+	case langfuse.SCORE_CREATE:
+		score := event.ScoreCreate.Body
+		s.addScore(score.Id, score.TraceId, score.Name, score.Value, score.DataType, score.ObservationId, score.Comment, timestamp)
+	case langfuse.SPAN_CREATE, langfuse.SPAN_UPDATE:
+		s.obsIDs = upsert(s.observations, s.obsIDs, id, string(api.ObservationTypeSpan), timestamp, body)
+	case langfuse.GENERATION_CREATE, langfuse.GENERATION_UPDATE:
+		s.obsIDs = upsert(s.observations, s.obsIDs, id, string(api.ObservationTypeGeneration), timestamp, body)
+	case langfuse.EVENT_CREATE:
+		s.obsIDs = upsert(s.observations, s.obsIDs, id, string(api.ObservationTypeEvent), timestamp, body)
+	case langfuse.OBSERVATION_CREATE, langfuse.OBSERVATION_UPDATE:
+		observationType, _ := body["type"].(string)
+		s.obsIDs = upsert(s.observations, s.obsIDs, id, observationType, timestamp, body)
+	}
+	//observations can arrive before their trace so the trace is created for them
+	if traceID, ok := body["traceId"].(string); ok && traceID != "" && event.Type != langfuse.TRACE_CREATE {
+		s.traceIDs = upsert(s.traces, s.traceIDs, traceID, "", timestamp, map[string]interface{}{"id": traceID})
+	}
+}
+
+func upsert(records map[string]*record, ids []string, id string, kind string, timestamp time.Time, body map[string]interface{}) []string {
+	r, ok := records[id]
+	if !ok {
+		r = &record{kind: kind, timestamp: timestamp, body: map[string]interface{}{}}
+		records[id] = r
+		ids = append(ids, id)
+	}
+	if kind != "" {
+		r.kind = kind
+	}
+	for key, value := range body {
+		if value != nil {
+			r.body[key] = value
+		}
+	}
+	return ids
+}
+
+func (s *Server) addScore(id *string, traceID string, name string, value *api.CreateScoreValue, dataType *api.ScoreDataType, observationID *string, comment *string, timestamp time.Time) *api.Score {
+	score := &api.Score{
+		Id:            ksuid.New().String(),
+		TraceId:       traceID,
+		Name:          name,
+		ObservationId: observationID,
+		Comment:       comment,
+		Timestamp:     timestamp,
+		DataType:      api.ScoreDataTypeNumeric,
+	}
+	if id != nil {
+		score.Id = *id
+	}
+	if value != nil {
+		value.Accept(&scoreValue{score: score})
+	}
+	if dataType != nil {
+		score.DataType = *dataType
+	}
+	//scores with the same id replace each other like they do in langfuse
+	for i, existing := range s.scores {
+		if existing.Id == score.Id {
+			s.scores[i] = score
+			return score
+		}
+	}
+	s.scores = append(s.scores, score)
+	return score
+}
+
+type scoreValue struct {
+	score *api.Score
+}
+
+func (v *scoreValue) VisitDouble(value float64) error {
+	v.score.Value = value
+	return nil
+}
+
+func (v *scoreValue) VisitString(value string) error {
+	v.score.StringValue = &value
+	v.score.DataType = api.ScoreDataTypeCategorical
+	return nil
+}
+
+func (s *Server) trace(id string) *api.TraceWithFullDetails {
+	r := s.traces[id]
+	body := copyBody(r.body)
+	body["timestamp"] = r.timestamp
+	trace := &api.TraceWithFullDetails{}
+	convert(body, trace)
+	for _, observationID := range s.obsIDs {
+		if observation := s.observation(observationID); observation.TraceId != nil && *observation.TraceId == id {
+			trace.Observations = append(trace.Observations, observation)
+		}
+	}
+	for _, score := range s.scores {
+		if score.TraceId == id {
+			trace.Scores = append(trace.Scores, score)
+		}
+	}
+	return trace
+}
+
+func (s *Server) observation(id string) *api.Observation {
+	r := s.observations[id]
+	body := copyBody(r.body)
+	body["type"] = r.kind
+	if body["startTime"] == nil {
+		body["startTime"] = r.timestamp
+	}
+	//the ingestion api accepts openai style usage but observations are read with langfuse usage
+	if usage, ok := body["usage"].(map[string]interface{}); ok {
+		for from, to := range map[string]string{"promptTokens": "input", "completionTokens": "output", "totalTokens": "total"} {
+			if value, ok := usage[from]; ok {
+				usage[to] = value
+				delete(usage, from)
+			}
+		}
+	}
+	observation := &api.Observation{}
+	convert(body, observation)
+	return observation
+}
+
+func (s *Server) listTraces(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var traces []*api.TraceWithDetails
+	for _, id := range s.traceIDs {
+		trace := s.trace(id)
+		if !matches(query.Get("userId"), trace.UserId) || !matches(query.Get("name"), trace.Name) || !matches(query.Get("sessionId"), trace.SessionId) || !matches(query.Get("release"), trace.Release) || !matches(query.Get("version"), trace.Version) {
+			continue
+		}
+		if !hasTags(trace.Tags, query["tags"]) || !inRange(trace.Timestamp, query.Get("fromTimestamp"), query.Get("toTimestamp")) {
+			continue
+		}
+		summary := &api.TraceWithDetails{}
+		convert(trace, summary)
+		summary.Observations = nil
+		summary.Scores = nil
+		for _, observation := range trace.Observations {
+			summary.Observations = append(summary.Observations, observation.Id)
+		}
+		for _, score := range trace.Scores {
+			summary.Scores = append(summary.Scores, score.Id)
+		}
+		traces = append(traces, summary)
+	}
+	page, meta := paginate(r, len(traces))
+	writeJSON(w, http.StatusOK, &api.Traces{Data: traces[page[0]:page[1]], Meta: meta})
+}
+
+func (s *Server) getTrace(w http.ResponseWriter, id string) {
+	if _, ok := s.traces[id]; !ok {
+		writeError(w, http.StatusNotFound, "trace not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.trace(id))
+}
+
+func (s *Server) listObservations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var observations []*api.Observation
+	for _, id := range s.obsIDs {
+		observation := s.observation(id)
+		if !matches(query.Get("name"), observation.Name) || !matches(query.Get("traceId"), observation.TraceId) || !matches(query.Get("parentObservationId"), observation.ParentObservationId) || !matches(query.Get("model"), observation.Model) || !matches(query.Get("version"), observation.Version) {
+			continue
+		}
+		if (query.Get("type") != "" && query.Get("type") != observation.Type) || (query.Get("level") != "" && query.Get("level") != string(observation.Level)) {
+			continue
+		}
+		if userID := query.Get("userId"); userID != "" {
+			if observation.TraceId == nil || s.traces[*observation.TraceId] == nil || s.traces[*observation.TraceId].body["userId"] != userID {
+				continue
+			}
+		}
+		if !inRange(observation.StartTime, query.Get("fromStartTime"), query.Get("toStartTime")) {
+			continue
+		}
+		observations = append(observations, observation)
+	}
+	page, meta := paginate(r, len(observations))
+	writeJSON(w, http.StatusOK, &api.Observations{Data: observations[page[0]:page[1]], Meta: meta})
+}
+
+func (s *Server) getObservation(w http.ResponseWriter, id string) {
+	if _, ok := s.observations[id]; !ok {
+		writeError(w, http.StatusNotFound, "observation not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.observation(id))
+}
+
+func (s *Server) getSession(w http.ResponseWriter, id string) {
+	session := &api.SessionWithTraces{Id: id, ProjectId: PROJECT_ID}
+	for _, traceID := range s.traceIDs {
+		if s.traces[traceID].body["sessionId"] != id {
+			continue
+		}
+		trace := &api.Trace{}
+		convert(s.trace(traceID), trace)
+		if session.CreatedAt.IsZero() || trace.Timestamp.Before(session.CreatedAt) {
+			session.CreatedAt = trace.Timestamp
+		}
+		session.Traces = append(session.Traces, trace)
+	}
+	if len(session.Traces) == 0 {
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, session)
+}
+
+func (s *Server) listScores(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var scores []*api.Score
+	for _, score := range s.scores {
+		if name := query.Get("name"); name != "" && score.Name != name {
+			continue
+		}
+		if userID := query.Get("userId"); userID != "" && (s.traces[score.TraceId] == nil || s.traces[score.TraceId].body["userId"] != userID) {
+			continue
+		}
+		scores = append(scores, score)
+	}
+	page, meta := paginate(r, len(scores))
+	writeJSON(w, http.StatusOK, &api.Scores{Data: scores[page[0]:page[1]], Meta: meta})
+}
+
+func (s *Server) createScore(w http.ResponseWriter, r *http.Request) {
+	request := &api.CreateScoreRequest{}
+	if !decode(w, r, request) {
+		return
+	}
+	if request.TraceId == "" || request.Name == "" {
+		writeError(w, http.StatusBadRequest, "traceId and name are required")
+		return
+	}
+	score := s.addScore(request.Id, request.TraceId, request.Name, request.Value, request.DataType, request.ObservationId, request.Comment, time.Now().UTC())
+	writeJSON(w, http.StatusOK, score)
+}
+
+func (s *Server) createDataset(w http.ResponseWriter, r *http.Request) {
+	request := &api.CreateDatasetRequest{}
+	if !decode(w, r, request) {
+		return
+	}
+	if request.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	dataset, ok := s.datasets[request.Name]
+	if !ok {
+		now := time.Now().UTC()
+		dataset = &api.Dataset{Id: ksuid.New().String(), Name: request.Name, ProjectId: PROJECT_ID, CreatedAt: now, UpdatedAt: now}
+		s.datasets[request.Name] = dataset
+	}
+	writeJSON(w, http.StatusOK, dataset)
+}
+
+func (s *Server) getDataset(w http.ResponseWriter, name string) {
+	dataset, ok := s.datasets[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, dataset)
+}
+
+func (s *Server) getRun(w http.ResponseWriter, datasetName string, runName string) {
+	dataset, ok := s.datasets[datasetName]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset not found")
+		return
+	}
+	run, ok := s.runs[dataset.Id+"/"+runName]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset run not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+func (s *Server) createItem(w http.ResponseWriter, r *http.Request) {
+	request := &api.CreateDatasetItemRequest{}
+	if !decode(w, r, request) {
+		return
+	}
+	dataset, ok := s.datasets[request.DatasetName]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset not found")
+		return
+	}
+	now := time.Now().UTC()
+	item := &api.DatasetItem{
+		Id:                  ksuid.New().String(),
+		Status:              api.DatasetStatusActive,
+		Input:               request.Input,
+		ExpectedOutput:      request.ExpectedOutput,
+		SourceTraceId:       request.SourceTraceId,
+		SourceObservationId: request.SourceObservationId,
+		DatasetId:           dataset.Id,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	if request.Id != nil {
+		item.Id = *request.Id
+	}
+	//items with the same id are updated
+	if existing, ok := s.items[item.Id]; ok {
+		item.CreatedAt = existing.CreatedAt
+		for i, datasetItem := range dataset.Items {
+			if datasetItem.Id == item.Id {
+				dataset.Items[i] = item
+			}
+		}
+	} else {
+		dataset.Items = append(dataset.Items, item)
+	}
+	s.items[item.Id] = item
+	dataset.UpdatedAt = now
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (s *Server) getItem(w http.ResponseWriter, id string) {
+	item, ok := s.items[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset item not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, item)
+}
+
+func (s *Server) createRunItem(w http.ResponseWriter, r *http.Request) {
+	request := &api.CreateDatasetRunItemRequest{}
+	if !decode(w, r, request) {
+		return
+	}
+	item, ok := s.items[request.DatasetItemId]
+	if !ok {
+		writeError(w, http.StatusNotFound, "dataset item not found")
+		return
+	}
+	if request.RunName == "" || request.ObservationId == "" {
+		writeError(w, http.StatusBadRequest, "runName and observationId are required")
+		return
+	}
+	now := time.Now().UTC()
+	key := item.DatasetId + "/" + request.RunName
+	run, ok := s.runs[key]
+	if !ok {
+		run = &api.DatasetRun{Id: ksuid.New().String(), Name: request.RunName, DatasetId: item.DatasetId, CreatedAt: now}
+		s.runs[key] = run
+		for _, dataset := range s.datasets {
+			if dataset.Id == item.DatasetId {
+				dataset.Runs = append(dataset.Runs, run.Name)
+			}
+		}
+	}
+	runItem := &api.DatasetRunItem{
+		Id:            ksuid.New().String(),
+		DatasetRunId:  run.Id,
+		DatasetItemId: item.Id,
+		ObservationId: request.ObservationId,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	run.DatasetRunItems = append(run.DatasetRunItems, runItem)
+	run.UpdatedAt = now
+	writeJSON(w, http.StatusOK, runItem)
+}
+
+// getPrompt returns the version asked for or the latest active version
+func (s *Server) getPrompt(w http.ResponseWriter, r *http.Request) {
+	versions := s.prompts[r.URL.Query().Get("name")]
+	if value := r.URL.Query().Get("version"); value != "" {
+		version, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "version must be a number")
+			return
+		}
+		for _, prompt := range versions {
+			if prompt.Version == version {
+				writeJSON(w, http.StatusOK, prompt)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "prompt not found")
+		return
+	}
+	if len(versions) == 0 {
+		writeError(w, http.StatusNotFound, "prompt not found")
+		return
+	}
+	active := versions[len(versions)-1]
+	for _, prompt := range versions {
+		if s.active[prompt] {
+			active = prompt
+		}
+	}
+	writeJSON(w, http.StatusOK, active)
+}
+
+func (s *Server) createPrompt(w http.ResponseWriter, r *http.Request) {
+	request := &api.CreatePromptRequest{}
+	if !decode(w, r, request) {
+		return
+	}
+	if request.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	prompt := &api.Prompt{Name: request.Name, Version: len(s.prompts[request.Name]) + 1, Prompt: request.Prompt}
+	s.prompts[request.Name] = append(s.prompts[request.Name], prompt)
+	s.active[prompt] = request.IsActive
+	writeJSON(w, http.StatusOK, prompt)
+}
+
+func matches(filter string, value *string) bool {
+	return filter == "" || (value != nil && *value == filter)
+}
+
+func hasTags(tags []string, filter []string) bool {
+	for _, tag := range filter {
+		found := false
+		for _, t := range tags {
+			found = found || t == tag
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func inRange(timestamp time.Time, from string, to string) bool {
+	if start, err := time.Parse(time.RFC3339Nano, from); err == nil && timestamp.Before(start) {
+		return false
+	}
+	if end, err := time.Parse(time.RFC3339Nano, to); err == nil && !timestamp.Before(end) {
+		return false
+	}
+	return true
+}
+
+// paginate returns the bounds of the requested page. Pages start at 1 and default to 50 items
+func paginate(r *http.Request, total int) ([2]int, *api.UtilsMetaResponse) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit < 1 {
+		limit = 50
+	}
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	return [2]int{start, end}, &api.UtilsMetaResponse{Page: page, Limit: limit, TotalItems: total, TotalPages: (total + limit - 1) / limit}
+}
+
+func copyBody(body map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(body))
+	for key, value := range body {
+		copied[key] = value
+	}
+	return copied
+}
+
+// convert copies a value into an api type with a json round trip
+func convert(from interface{}, to interface{}) {
+	data, _ := json.Marshal(from)
+	_ = json.Unmarshal(data, to)
+}
+
+func decode(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
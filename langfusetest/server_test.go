@@ -0,0 +1,167 @@
+package langfusetest_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wepala/langfuse-go/api"
+	"github.com/wepala/langfuse-go/langfuse"
+	"github.com/wepala/langfuse-go/langfusetest"
+)
+
+// waitForEvents waits for the batch event manager to send the events
+func waitForEvents(t *testing.T, server *langfusetest.Server, count int) {
+	deadline := time.Now().Add(2 * time.Second)
+	for len(server.Events())+len(server.Rejected()) < count {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d events to be ingested, got %d", count, len(server.Events()))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestServer_Ingestion(t *testing.T) {
+	t.Run("should serve what the sdk ingested", func(t *testing.T) {
+		server := langfusetest.NewServer()
+		defer server.Close()
+		sdk := langfuse.New(context.TODO(), server.Options())
+		session := sdk.Session("session-id", "user-id")
+		trace, _ := session.Trace(context.TODO(), &langfuse.Trace{BasicObservation: langfuse.BasicObservation{Name: "chat"}})
+		span, _ := trace.Span(&langfuse.Span{BasicObservation: langfuse.BasicObservation{Name: "retrieve"}})
+		generation, _ := span.Generation(&langfuse.Generation{
+			BasicObservation: langfuse.BasicObservation{Name: "answer"},
+			Model:            "gpt-4o",
+			Usage:            map[string]interface{}{"promptTokens": 10, "completionTokens": 2},
+		})
+		_ = generation.EndWithOutput("hello")
+		_ = span.End()
+		_, _ = trace.Score(&langfuse.Score{BasicObservation: langfuse.BasicObservation{Name: "quality"}, Value: 0.5})
+		sdk.EventManager().Flush(context.TODO())
+		waitForEvents(t, server, 6)
+		if len(server.Rejected()) != 0 {
+			t.Fatalf("expected all events to be accepted, got %s", *server.Rejected()[0].Message)
+		}
+
+		details, err := sdk.Client().Trace.Get(context.TODO(), trace.ID)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if *details.Name != "chat" || *details.UserId != "user-id" || len(details.Observations) != 2 || len(details.Scores) != 1 {
+			t.Errorf("expected the trace with its observations and scores, got %v", details)
+		}
+		observation, err := sdk.Client().Observations.Get(context.TODO(), generation.ID)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if observation.Type != "GENERATION" || *observation.ParentObservationId != span.ID || observation.EndTime == nil || *observation.Usage.Input != 10 {
+			t.Errorf("expected the generation with its updates, got %v", observation)
+		}
+		generationType := "GENERATION"
+		observations, err := sdk.Client().Observations.Getmany(context.TODO(), &api.ObservationsGetManyRequest{Type: &generationType})
+		if err != nil || len(observations.Data) != 1 || observations.Meta.TotalItems != 1 {
+			t.Errorf("expected observations to be filtered by type, got %v %v", observations, err)
+		}
+		userID := "user-id"
+		traces, err := sdk.Client().Trace.List(context.TODO(), &api.TraceListRequest{UserId: &userID})
+		if err != nil || len(traces.Data) != 1 || len(traces.Data[0].Observations) != 2 {
+			t.Errorf("expected the trace to be listed, got %v %v", traces, err)
+		}
+		conversation, err := session.Conversation(context.TODO())
+		if err != nil || len(conversation.Turns) != 1 || len(conversation.Turns[0].Generations) != 1 {
+			t.Errorf("expected the session to be read back, got %v %v", conversation, err)
+		}
+	})
+	t.Run("should reject events that don't match the api", func(t *testing.T) {
+		server := langfusetest.NewServer()
+		defer server.Close()
+		request, _ := http.NewRequest(http.MethodPost, server.URL+"/api/public/ingestion", strings.NewReader(`{"batch":[
+			{"id":"1","type":"span-create","timestamp":"2024-01-01T00:00:00Z","body":{"id":"span-id","start_time":"2024-01-01T00:00:00Z"}},
+			{"id":"2","type":"span-update","timestamp":"2024-01-01T00:00:00Z","body":{"name":"no id"}},
+			{"id":"3","type":"unknown","timestamp":"2024-01-01T00:00:00Z","body":{}},
+			{"id":"4","type":"trace-create","timestamp":"yesterday","body":{"id":"trace-id"}},
+			{"id":"5","type":"trace-create","timestamp":"2024-01-01T00:00:00Z","body":{"id":"trace-id"}}
+		]}`))
+		request.SetBasicAuth("pk", "sk")
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		if response.StatusCode != http.StatusMultiStatus {
+			t.Errorf("expected status %d, got %d", http.StatusMultiStatus, response.StatusCode)
+		}
+		rejected := server.Rejected()
+		if len(rejected) != 4 || len(server.Events()) != 1 {
+			t.Fatalf("expected %d events to be rejected, got %d", 4, len(rejected))
+		}
+		if !strings.Contains(*rejected[0].Message, "start_time") {
+			t.Errorf("expected the unknown field to be reported, got %s", *rejected[0].Message)
+		}
+	})
+	t.Run("should require basic auth", func(t *testing.T) {
+		server := langfusetest.NewServer()
+		defer server.Close()
+		response, err := http.Get(server.URL + "/api/public/health")
+		if err != nil || response.StatusCode != http.StatusUnauthorized {
+			t.Errorf("expected the request to be unauthorized, got %v %v", response, err)
+		}
+	})
+}
+
+func TestServer_Datasets(t *testing.T) {
+	t.Run("should store datasets, items, runs and prompts", func(t *testing.T) {
+		server := langfusetest.NewServer()
+		defer server.Close()
+		client := langfuse.New(context.TODO(), server.Options()).Client()
+		dataset, err := client.Datasets.Create(context.TODO(), &api.CreateDatasetRequest{Name: "questions"})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		item, err := client.Datasetitems.Create(context.TODO(), &api.CreateDatasetItemRequest{DatasetName: "questions", Input: "hi"})
+		if err != nil || item.DatasetId != dataset.Id {
+			t.Fatalf("expected the item to be added to the dataset, got %v %v", item, err)
+		}
+		_, err = client.Datasetrunitems.Create(context.TODO(), &api.CreateDatasetRunItemRequest{RunName: "run-1", DatasetItemId: item.Id, ObservationId: "observation-id"})
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+		run, err := client.Datasets.Getruns(context.TODO(), "questions", "run-1")
+		if err != nil || len(run.DatasetRunItems) != 1 {
+			t.Errorf("expected the run to have the item, got %v %v", run, err)
+		}
+		dataset, _ = client.Datasets.Get(context.TODO(), "questions")
+		if len(dataset.Items) != 1 || len(dataset.Runs) != 1 {
+			t.Errorf("expected the dataset to have the item and run, got %v", dataset)
+		}
+
+		_, _ = client.Prompts.Create(context.TODO(), &api.CreatePromptRequest{Name: "greeting", Prompt: "hello", IsActive: true})
+		_, _ = client.Prompts.Create(context.TODO(), &api.CreatePromptRequest{Name: "greeting", Prompt: "hi"})
+		prompt, err := client.Prompts.Get(context.TODO(), &api.PromptsGetRequest{Name: "greeting"})
+		if err != nil || prompt.Version != 1 {
+			t.Errorf("expected the active version to be returned, got %v %v", prompt, err)
+		}
+		version := 2
+		prompt, _ = client.Prompts.Get(context.TODO(), &api.PromptsGetRequest{Name: "greeting", Version: &version})
+		if prompt.Prompt != "hi" {
+			t.Errorf("expected version %d to be returned, got %v", 2, prompt)
+		}
+	})
+}
+
+func TestServer_Scores(t *testing.T) {
+	t.Run("should create numeric and categorical scores", func(t *testing.T) {
+		server := langfusetest.NewServer()
+		defer server.Close()
+		client := langfuse.New(context.TODO(), server.Options()).Client()
+		score, err := client.Score.Create(context.TODO(), (&langfuse.Score{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id", Name: "quality"}, Value: 0.5}).CreateRequest())
+		if err != nil || score.Value != 0.5 {
+			t.Errorf("expected a numeric score to be created, got %v %v", score, err)
+		}
+		score, err = client.Score.Create(context.TODO(), (&langfuse.Score{BasicObservation: langfuse.BasicObservation{TraceID: "trace-id", Name: "sentiment"}, StringValue: "positive"}).CreateRequest())
+		if err != nil || score.StringValue == nil || *score.StringValue != "positive" {
+			t.Errorf("expected a categorical score to be created, got %v %v", score, err)
+		}
+	})
+}